@@ -199,18 +199,18 @@ func compactMarketName(name string, maxLen int) string {
 
 // displayMarksTables displays marks in a formatted table
 func displayMarksTables(result *endpoints.SimulationResult) {
-	// Group marks by team
+	// Group marks by team (the price sheet only includes non-zero marks)
 	teamMarks := make(map[string]map[string]float64)
+	teamExpPoints := make(map[string]float64)
 	marketNames := make(map[string]bool)
 	
-	for _, mark := range result.OutrightMarks {
-		if mark.Mark > 0 { // Only include non-zero marks
-			if teamMarks[mark.Team] == nil {
-				teamMarks[mark.Team] = make(map[string]float64)
-			}
-			teamMarks[mark.Team][mark.Market] = mark.Mark
-			marketNames[mark.Market] = true
+	for _, row := range result.PriceSheet(0) {
+		if teamMarks[row.Team] == nil {
+			teamMarks[row.Team] = make(map[string]float64)
 		}
+		teamMarks[row.Team][row.Market] = row.Probability
+		teamExpPoints[row.Team] = row.ExpectedPoints
+		marketNames[row.Market] = true
 	}
 	
 	if len(teamMarks) == 0 {
@@ -225,12 +225,6 @@ func displayMarksTables(result *endpoints.SimulationResult) {
 	}
 	sort.Strings(markets)
 	
-	// Create team lookup for expected points
-	teamExpPoints := make(map[string]float64)
-	for _, team := range result.Teams {
-		teamExpPoints[team.Name] = team.ExpectedSeasonPoints
-	}
-	
 	log.Println()
 	log.Println("📊 MARK VALUES TABLE")
 	
@@ -0,0 +1,47 @@
+package endpoints
+
+import (
+	"log"
+
+	"github.com/jhw/go-outrights/pkg/outrights"
+)
+
+// TableAccuracyPoint scores the expected table projected from one cutoff date
+type TableAccuracyPoint struct {
+	CutoffDate    string  `json:"cutoff_date"`
+	PositionError float64 `json:"position_error"` // Mean absolute position error against the final table
+}
+
+// BacktestTableAccuracy projects the expected final table from each cutoff date and
+// scores it against the actual final table built from all results, which should cover
+// the full season. Uses opts.TieBreakers for the actual table; opts.CutoffDate is
+// overridden per run. Stops at the first failed projection
+func BacktestTableAccuracy(results []outrights.Result, events []outrights.Event, cutoffDates []string, opts SimOptions) ([]TableAccuracyPoint, error) {
+	teamNamesMap := make(map[string]bool)
+	for _, result := range results {
+		homeTeam, awayTeam := result.Teams()
+		teamNamesMap[homeTeam] = true
+		teamNamesMap[awayTeam] = true
+	}
+	teamNames := make([]string, 0, len(teamNamesMap))
+	for name := range teamNamesMap {
+		teamNames = append(teamNames, name)
+	}
+	actual := outrights.CalcLeagueTableWithTieBreakers(teamNames, results, nil, opts.TieBreakers)
+
+	points := make([]TableAccuracyPoint, 0, len(cutoffDates))
+	for _, cutoffDate := range cutoffDates {
+		opts.CutoffDate = cutoffDate
+		predicted, err := ExpectedTable(results, events, opts)
+		if err != nil {
+			return nil, err
+		}
+		point := TableAccuracyPoint{
+			CutoffDate:    cutoffDate,
+			PositionError: outrights.TableAccuracy(predicted, actual),
+		}
+		log.Printf("Backtest from %s: mean position error %.2f", cutoffDate, point.PositionError)
+		points = append(points, point)
+	}
+	return points, nil
+}
@@ -0,0 +1,38 @@
+package endpoints
+
+import (
+	"testing"
+)
+
+func TestBacktestTableAccuracy(t *testing.T) {
+	results, events, _ := smallSeason()
+	finalScores := map[string][]int{
+		"B vs A": {1, 0}, "D vs C": {0, 2}, "C vs A": {0, 1},
+		"D vs B": {1, 1}, "D vs A": {0, 3}, "C vs B": {2, 2},
+	}
+	for i := range results {
+		if score, exists := finalScores[results[i].Name]; exists {
+			results[i].Score = score
+		}
+	}
+
+	// Final table A 13, B 11, C 6, D 2, which a cutoff after the last game reproduces
+	opts := SimOptions{Generations: 20, Seed: 1}
+	points, err := BacktestTableAccuracy(results, events, []string{"2024-08-31", "2024-12-31"}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 2 || points[0].CutoffDate != "2024-08-31" || points[1].CutoffDate != "2024-12-31" {
+		t.Fatalf("expected a point per cutoff date in order, got %+v", points)
+	}
+	if points[0].PositionError < 0 || points[0].PositionError > 1 {
+		t.Errorf("mid-season position error %g, expected at most one place on average", points[0].PositionError)
+	}
+	if points[1].PositionError != 0 {
+		t.Errorf("end-of-season position error %g, want 0", points[1].PositionError)
+	}
+
+	if _, err := BacktestTableAccuracy(results, events, []string{"2024-08-31", "2024-08-01"}, opts); err == nil {
+		t.Error("expected an error from a cutoff before any training events")
+	}
+}
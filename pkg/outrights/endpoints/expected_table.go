@@ -0,0 +1,17 @@
+package endpoints
+
+import (
+	"github.com/jhw/go-outrights/pkg/outrights"
+)
+
+// ExpectedTable solves ratings and projects the final table from deterministic expected
+// season points and goal difference, without Monte Carlo simulation. Much faster than
+// SimulateSeason for a quick preview; the returned teams have no position probabilities
+func ExpectedTable(results []outrights.Result, events []outrights.Event, opts SimOptions) ([]outrights.Team, error) {
+	opts.ExpectedOnly = true
+	result, err := SimulateSeason(results, events, nil, nil, opts)
+	if err != nil {
+		return nil, err
+	}
+	return result.Teams, nil
+}
@@ -0,0 +1,44 @@
+package endpoints
+
+import (
+	"math"
+	"testing"
+)
+
+func TestExpectedTable(t *testing.T) {
+	results, events, _ := smallSeason()
+	teams, err := ExpectedTable(results, events, SimOptions{Generations: 20, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(teams) != 4 {
+		t.Fatalf("expected 4 teams, got %d", len(teams))
+	}
+
+	for i, team := range teams {
+		if team.PositionProbabilities != nil {
+			t.Errorf("%s has position probabilities %v without simulation", team.Name, team.PositionProbabilities)
+		}
+		if i > 0 && team.ExpectedSeasonPoints > teams[i-1].ExpectedSeasonPoints {
+			t.Errorf("%s (%.3f) ranked below %s (%.3f)",
+				team.Name, team.ExpectedSeasonPoints, teams[i-1].Name, teams[i-1].ExpectedSeasonPoints)
+		}
+		if got := team.ExpectedSeasonPoints - float64(team.Points); math.Abs(got-team.ProjectedRemainingPoints) > 1e-12 {
+			t.Errorf("%s projects %.6f remaining points, expected %.6f", team.Name, team.ProjectedRemainingPoints, got)
+		}
+		if team.ExpectedPointsBreakdown == nil || len(team.ExpectedPointsBreakdown.Fixtures) != 3 {
+			t.Errorf("%s breakdown should cover its 3 remaining fixtures, got %+v", team.Name, team.ExpectedPointsBreakdown)
+		}
+	}
+
+	simulated, err := SimulateSeason(results, events, nil, nil, SimOptions{Generations: 20, Seed: 1, ExpectedOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, team := range simulated.Teams {
+		if teams[i].Name != team.Name || teams[i].ExpectedSeasonPoints != team.ExpectedSeasonPoints {
+			t.Errorf("position %d: ExpectedTable has %s %.6f, SimulateSeason has %s %.6f",
+				i, teams[i].Name, teams[i].ExpectedSeasonPoints, team.Name, team.ExpectedSeasonPoints)
+		}
+	}
+}
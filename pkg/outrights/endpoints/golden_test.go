@@ -0,0 +1,138 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/jhw/go-outrights/pkg/outrights"
+)
+
+var update = flag.Bool("update", false, "Rewrite testdata/ENG1-golden.json from this run instead of comparing")
+
+// Tolerances absorb floating-point drift across architectures (e.g. FMA on arm64) and
+// harmless reorderings of arithmetic. Expected points run to ~100, so they get a
+// proportionally wider band than marks
+const (
+	goldenFile            = "testdata/ENG1-golden.json"
+	goldenMarkTolerance   = 1e-6
+	goldenPointsTolerance = 1e-4
+)
+
+// golden holds the serialized outputs compared between runs
+type golden struct {
+	Marks          map[string]float64 `json:"marks"`           // Keyed "Market: Team"
+	ExpectedPoints map[string]float64 `json:"expected_points"` // Keyed by team
+}
+
+// loadENG1 reads the ENG1 fixture inputs shared by the endpoint tests
+func loadENG1(t testing.TB) ([]outrights.Result, []outrights.Event, []outrights.Market) {
+	t.Helper()
+	var results []outrights.Result
+	var events []outrights.Event
+	var markets []outrights.Market
+	for file, v := range map[string]interface{}{
+		"../../../fixtures/ENG1-results.json":         &results,
+		"../../../fixtures/ENG1-training-events.json": &events,
+		"../../../fixtures/ENG1-markets.json":         &markets,
+	} {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(data, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return results, events, markets
+}
+
+func TestGoldenENG1(t *testing.T) {
+	results, events, markets := loadENG1(t)
+
+	// Fixed seed and a modest configuration so the run is quick and reproducible
+	result, err := SimulateSeason(results, events, markets, nil, SimOptions{
+		Generations: 100,
+		NPaths:      2000,
+		Seed:        42,
+	})
+	if err != nil {
+		t.Fatalf("simulation error: %v", err)
+	}
+
+	current := golden{
+		Marks:          make(map[string]float64),
+		ExpectedPoints: make(map[string]float64),
+	}
+	for _, mark := range result.OutrightMarks {
+		current.Marks[fmt.Sprintf("%s: %s", mark.Market, mark.Team)] = mark.Mark
+	}
+	for _, team := range result.Teams {
+		current.ExpectedPoints[team.Name] = team.ExpectedSeasonPoints
+	}
+
+	if *update {
+		data, err := json.MarshalIndent(current, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(goldenFile, append(data, '\n'), 0644); err != nil {
+			t.Fatal(err)
+		}
+		t.Logf("wrote %s", goldenFile)
+		return
+	}
+
+	data, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want golden
+	if err := json.Unmarshal(data, &want); err != nil {
+		t.Fatal(err)
+	}
+
+	failures := compareValues("mark", want.Marks, current.Marks, goldenMarkTolerance)
+	failures = append(failures, compareValues("expected points", want.ExpectedPoints, current.ExpectedPoints, goldenPointsTolerance)...)
+	for _, failure := range failures {
+		t.Error(failure)
+	}
+	if len(failures) > 0 {
+		t.Fatalf("%d values differ from %s beyond tolerance; rerun with -update if the change is intended", len(failures), goldenFile)
+	}
+}
+
+// compareValues lists every key missing from either side or differing beyond tolerance
+func compareValues(label string, expected, actual map[string]float64, tolerance float64) []string {
+	keys := make(map[string]bool)
+	for key := range expected {
+		keys[key] = true
+	}
+	for key := range actual {
+		keys[key] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	var failures []string
+	for _, key := range sorted {
+		want, inExpected := expected[key]
+		got, inActual := actual[key]
+		switch {
+		case !inExpected:
+			failures = append(failures, fmt.Sprintf("unexpected %s %s = %.6f", label, key, got))
+		case !inActual:
+			failures = append(failures, fmt.Sprintf("missing %s %s (expected %.6f)", label, key, want))
+		case math.Abs(got-want) > tolerance:
+			failures = append(failures, fmt.Sprintf("%s %s: expected %.6f, got %.6f", label, key, want, got))
+		}
+	}
+	return failures
+}
@@ -0,0 +1,407 @@
+package endpoints
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/jhw/go-outrights/pkg/outrights"
+)
+
+// Bet represents a stake on a team in an outright market at a decimal price
+type Bet struct {
+	Market string  `json:"market"`
+	Team   string  `json:"team"`
+	Stake  float64 `json:"stake"`
+	Price  float64 `json:"price"`
+}
+
+// PortfolioStats holds the moments of a portfolio's return across simulated paths
+type PortfolioStats struct {
+	ExpectedReturn float64 `json:"expected_return"`
+	Variance       float64 `json:"variance"`
+	StdDev         float64 `json:"std_dev"`
+}
+
+// PriceRow is a single team's price in an outright market
+type PriceRow struct {
+	Market         string  `json:"market"`
+	Team           string  `json:"team"`
+	ExpectedPoints float64 `json:"expected_points"`
+	Probability    float64 `json:"probability"`
+	FairPrice      float64 `json:"fair_price"`
+	Price          float64 `json:"price"` // Fair price with the margin applied
+}
+
+// MarkDelta is the change in one team's outright mark between two results
+type MarkDelta struct {
+	Market string  `json:"market"`
+	Team   string  `json:"team"`
+	Old    float64 `json:"old"`
+	New    float64 `json:"new"`
+	Delta  float64 `json:"delta"` // New - Old
+}
+
+// Per-path quantities a Condition can test
+const (
+	ConditionPosition       = "position"        // Finishing position (1-based) at most Value
+	ConditionPoints         = "points"          // Final points at least Value
+	ConditionGoalDifference = "goal_difference" // Final goal difference at least Value
+)
+
+// Condition is a per-path predicate on one team's simulated season, e.g. {"Team A",
+// "position", 1} for winning the league
+type Condition struct {
+	Team     string `json:"team"`
+	Quantity string `json:"quantity"`
+	Value    int    `json:"value"`
+}
+
+// MiniLeague calculates finishing position probabilities within an ad-hoc subset of
+// teams, e.g. "who finishes highest of these three", from the simulated paths
+// Requires RetainSimPoints; returns an empty map otherwise
+func (r SimulationResult) MiniLeague(teams []string) map[string][]float64 {
+	if r.SimPoints == nil {
+		return make(map[string][]float64)
+	}
+	return r.SimPoints.PositionProbabilities(teams)
+}
+
+// PortfolioStats calculates the expected return and variance of a set of outright bets
+// across the simulated paths, capturing correlation between markets (e.g. the same team
+// winning the title and finishing top four). Requires RetainSimPoints, and errors
+// without it or on a bet naming an unknown market or team
+func (r SimulationResult) PortfolioStats(bets []Bet) (PortfolioStats, error) {
+	if r.SimPoints == nil {
+		return PortfolioStats{}, errors.New("portfolio stats require RetainSimPoints")
+	}
+	
+	_, _, nPaths := r.SimPoints.GetSimulationData()
+	returns := make([]float64, nPaths)
+	positionsCache := make(map[string]map[string][]int)
+	
+	for _, bet := range bets {
+		payoffs, err := r.pathPayoffs(bet.Market, bet.Team, positionsCache)
+		if err != nil {
+			return PortfolioStats{}, err
+		}
+		for path, payoff := range payoffs {
+			returns[path] += bet.Stake*bet.Price*payoff - bet.Stake
+		}
+	}
+	
+	mean := 0.0
+	for _, ret := range returns {
+		mean += ret
+	}
+	mean /= float64(nPaths)
+	
+	variance := 0.0
+	for _, ret := range returns {
+		variance += (ret - mean) * (ret - mean)
+	}
+	variance /= float64(nPaths)
+	
+	return PortfolioStats{
+		ExpectedReturn: mean,
+		Variance:       variance,
+		StdDev:         math.Sqrt(variance),
+	}, nil
+}
+
+// pathPayoffs returns a team's payoff in the named market on every retained path,
+// caching per-path positions by market
+func (r SimulationResult) pathPayoffs(marketName, team string, positionsCache map[string]map[string][]int) ([]float64, error) {
+	var market *outrights.Market
+	for i := range r.Markets {
+		if r.Markets[i].Name == marketName {
+			market = &r.Markets[i]
+			break
+		}
+	}
+	if market == nil {
+		return nil, fmt.Errorf("unknown market: %s", marketName)
+	}
+	
+	if _, exists := positionsCache[marketName]; !exists {
+		positionsCache[marketName] = r.SimPoints.PathPositions(market.Teams)
+	}
+	positions, exists := positionsCache[marketName][team]
+	if !exists {
+		return nil, fmt.Errorf("%s market has unknown team %s", marketName, team)
+	}
+	
+	payoffs := make([]float64, len(positions))
+	for path, pos := range positions {
+		payoffs[path] = market.ParsedPayoff[pos]
+	}
+	return payoffs, nil
+}
+
+// MarketCorrelations calculates the pairwise correlation of market payoffs across the
+// simulated paths, keyed "market: team", e.g. between "Winner: Team A" and "Relegation:
+// Team B". Outcomes that never vary have zero correlation. Requires RetainSimPoints
+func (r SimulationResult) MarketCorrelations(markets []outrights.Market) (map[string]map[string]float64, error) {
+	if r.SimPoints == nil {
+		return nil, errors.New("market correlations require RetainSimPoints")
+	}
+	
+	// Initialize a copy so callers can pass raw market definitions
+	teamNames, _, nPaths := r.SimPoints.GetSimulationData()
+	initialized := make([]outrights.Market, len(markets))
+	copy(initialized, markets)
+	if err := outrights.InitMarkets(teamNames, initialized); err != nil {
+		return nil, err
+	}
+	
+	// Collect centred payoff series for every market/team outcome
+	var keys []string
+	centred := make(map[string][]float64)
+	norms := make(map[string]float64)
+	for _, market := range initialized {
+		positions := r.SimPoints.PathPositions(market.Teams)
+		for _, team := range market.Teams {
+			key := fmt.Sprintf("%s: %s", market.Name, team)
+			series := make([]float64, nPaths)
+			mean := 0.0
+			for path, pos := range positions[team] {
+				series[path] = market.ParsedPayoff[pos]
+				mean += series[path]
+			}
+			mean /= float64(nPaths)
+			
+			norm := 0.0
+			for path := range series {
+				series[path] -= mean
+				norm += series[path] * series[path]
+			}
+			
+			keys = append(keys, key)
+			centred[key] = series
+			norms[key] = math.Sqrt(norm)
+		}
+	}
+	
+	correlations := make(map[string]map[string]float64)
+	for _, a := range keys {
+		correlations[a] = make(map[string]float64)
+	}
+	for i, a := range keys {
+		for _, b := range keys[i:] {
+			correlation := 0.0
+			if norms[a] > 0 && norms[b] > 0 {
+				dot := 0.0
+				for path := range centred[a] {
+					dot += centred[a][path] * centred[b][path]
+				}
+				correlation = dot / (norms[a] * norms[b])
+			}
+			correlations[a][b] = correlation
+			correlations[b][a] = correlation
+		}
+	}
+	
+	return correlations, nil
+}
+
+// PriceSheet converts outright marks into a bookmaker price sheet, grouped by market
+// (sorted by name) with teams sorted by expected season points descending
+// Each price is shortened proportionally by margin, e.g. 0.05 for a 5% overround;
+// teams with a zero mark have no price and are omitted
+func (r SimulationResult) PriceSheet(margin float64) []PriceRow {
+	expectedPoints := make(map[string]float64)
+	for _, team := range r.Teams {
+		expectedPoints[team.Name] = team.ExpectedSeasonPoints
+	}
+	
+	var rows []PriceRow
+	for _, mark := range r.OutrightMarks {
+		if mark.Mark <= 0 {
+			continue
+		}
+		rows = append(rows, PriceRow{
+			Market:         mark.Market,
+			Team:           mark.Team,
+			ExpectedPoints: expectedPoints[mark.Team],
+			Probability:    mark.Mark,
+			FairPrice:      1.0 / mark.Mark,
+			Price:          1.0 / (mark.Mark * (1 + margin)),
+		})
+	}
+	
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].Market == rows[j].Market {
+			return rows[i].ExpectedPoints > rows[j].ExpectedPoints
+		}
+		return rows[i].Market < rows[j].Market
+	})
+	
+	return rows
+}
+
+// PriceFixture prices a hypothetical home vs away fixture from the fitted ratings and
+// home advantage model, with the same draw adjustments and price format as the run's
+// fixture odds, plus the full correct score grid
+func (r SimulationResult) PriceFixture(home, away string) (outrights.FixtureOdds, error) {
+	if home == away {
+		return outrights.FixtureOdds{}, fmt.Errorf("cannot price %s against itself", home)
+	}
+	
+	// Undo any reporting shift to recover the ratings the run priced with
+	ratings := make(map[string]float64)
+	for _, team := range r.Teams {
+		ratings[team.Name] = team.PoissonRating - r.RatingsOffset
+	}
+	for _, name := range []string{home, away} {
+		if _, exists := ratings[name]; !exists {
+			return outrights.FixtureOdds{}, fmt.Errorf("unknown team %s", name)
+		}
+	}
+	
+	shootoutHomeShare := 0.5
+	if r.UsedOptions.ShootoutHomeShare != nil {
+		shootoutHomeShare = *r.UsedOptions.ShootoutHomeShare
+	}
+	fixtureOdds := []outrights.FixtureOdds{
+		outrights.CalcFixtureOddsWithModel(home+" vs "+away, ratings, r.HomeModel),
+	}
+	adjustDrawOdds(fixtureOdds, SimulationRequest{
+		NoDraws:           r.UsedOptions.NoDraws,
+		ShootoutHomeShare: shootoutHomeShare,
+		DrawInflation:     r.UsedOptions.DrawInflation,
+	})
+	if r.UsedOptions.PriceFormat != "" {
+		fixtureOdds[0].Prices = outrights.FormatPrices(fixtureOdds[0].Probabilities[:], r.UsedOptions.PriceFormat)
+	}
+	fixtureOdds[0].CorrectScores = r.HomeModel.NewTeamsScoreMatrix(home, away, ratings).Grid()
+	
+	return fixtureOdds[0], nil
+}
+
+// HeadToHeadExpectedGoals sums the expected goals of teams a and b across their remaining
+// meetings, from the lambdas of RemainingFixtureOdds; add played meetings with
+// outrights.HeadToHeadGoals for a season aggregate. Unknown teams or no meetings give zeros
+func (r SimulationResult) HeadToHeadExpectedGoals(a, b string) (aGoals, bGoals float64) {
+	for _, odds := range r.RemainingFixtureOdds {
+		homeTeam, awayTeam := outrights.ParseEventName(odds.Fixture)
+		if homeTeam == a && awayTeam == b {
+			aGoals += odds.Lambdas[0]
+			bGoals += odds.Lambdas[1]
+		} else if homeTeam == b && awayTeam == a {
+			aGoals += odds.Lambdas[1]
+			bGoals += odds.Lambdas[0]
+		}
+	}
+	return aGoals, bGoals
+}
+
+// JointProbability calculates the fraction of simulated paths on which every condition
+// holds, e.g. a team winning the league and reaching 90 points. Positions are within the
+// full league. Requires RetainSimPoints
+func (r SimulationResult) JointProbability(conditions []Condition) (float64, error) {
+	if r.SimPoints == nil {
+		return 0, errors.New("joint probability requires RetainSimPoints")
+	}
+	
+	teamNames, points, nPaths := r.SimPoints.GetSimulationData()
+	teamIndices := make(map[string]int, len(teamNames))
+	for i, name := range teamNames {
+		teamIndices[name] = i
+	}
+	
+	var positions map[string][]int
+	predicates := make([]func(path int) bool, len(conditions))
+	for i, condition := range conditions {
+		idx, exists := teamIndices[condition.Team]
+		if !exists {
+			return 0, fmt.Errorf("condition has unknown team %s", condition.Team)
+		}
+		value := condition.Value
+		switch condition.Quantity {
+		case ConditionPosition:
+			if positions == nil {
+				positions = r.SimPoints.PathPositions(nil)
+			}
+			teamPositions := positions[condition.Team]
+			predicates[i] = func(path int) bool { return teamPositions[path]+1 <= value }
+		case ConditionPoints:
+			predicates[i] = func(path int) bool { return points[idx][path] >= value }
+		case ConditionGoalDifference:
+			goalDifference := r.SimPoints.GoalDifference[idx]
+			predicates[i] = func(path int) bool { return goalDifference[path] >= value }
+		default:
+			return 0, fmt.Errorf("unknown condition quantity %s", condition.Quantity)
+		}
+	}
+	
+	hits := 0
+	for path := 0; path < nPaths; path++ {
+		holds := true
+		for _, predicate := range predicates {
+			if !predicate(path) {
+				holds = false
+				break
+			}
+		}
+		if holds {
+			hits++
+		}
+	}
+	
+	return float64(hits) / float64(nPaths), nil
+}
+
+// DiffResults compares the outright marks of two results, e.g. before and after a data
+// refresh, returning every market/team pair sorted by absolute change, largest first
+// A pair missing from one result counts as a mark of 0 there
+func DiffResults(a, b SimulationResult) []MarkDelta {
+	type markKey struct{ market, team string }
+	deltas := make(map[markKey]*MarkDelta)
+	var keys []markKey
+	
+	lookup := func(mark outrights.OutrightMark) *MarkDelta {
+		key := markKey{mark.Market, mark.Team}
+		if _, exists := deltas[key]; !exists {
+			deltas[key] = &MarkDelta{Market: mark.Market, Team: mark.Team}
+			keys = append(keys, key)
+		}
+		return deltas[key]
+	}
+	for _, mark := range a.OutrightMarks {
+		lookup(mark).Old = mark.Mark
+	}
+	for _, mark := range b.OutrightMarks {
+		lookup(mark).New = mark.Mark
+	}
+	
+	result := make([]MarkDelta, 0, len(keys))
+	for _, key := range keys {
+		delta := deltas[key]
+		delta.Delta = delta.New - delta.Old
+		result = append(result, *delta)
+	}
+	
+	sort.SliceStable(result, func(i, j int) bool {
+		return math.Abs(result[i].Delta) > math.Abs(result[j].Delta)
+	})
+	
+	return result
+}
+
+// MarketRankCorrelation compares the model's expected-points ordering of teams with the
+// ordering implied by outright winner prices, as a Spearman rank correlation; teams
+// without a price are left out
+func (r SimulationResult) MarketRankCorrelation(winnerPrices map[string]float64) float64 {
+	teams := make([]outrights.Team, len(r.Teams))
+	copy(teams, r.Teams)
+	sort.SliceStable(teams, func(i, j int) bool {
+		return teams[i].ExpectedSeasonPoints > teams[j].ExpectedSeasonPoints
+	})
+	
+	modelRanking := make([]string, len(teams))
+	for i, team := range teams {
+		modelRanking[i] = team.Name
+	}
+	return outrights.RankCorrelation(modelRanking, outrights.MarketImpliedRanking(winnerPrices))
+}
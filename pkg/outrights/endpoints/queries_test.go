@@ -0,0 +1,345 @@
+package endpoints
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/jhw/go-outrights/pkg/outrights"
+)
+
+// pathsResult returns a result retaining four hand-built paths for teams A, B and C:
+// A wins the first two, B the third and C the fourth, where A finishes bottom
+func pathsResult(t *testing.T) SimulationResult {
+	t.Helper()
+	teams := []outrights.Team{{Name: "A"}, {Name: "B"}, {Name: "C"}}
+	simPoints := outrights.NewSimPoints(teams, 4)
+	simPoints.Points = [][]int{
+		{10, 10, 5, 1},
+		{5, 5, 10, 5},
+		{1, 1, 1, 10},
+	}
+	simPoints.GoalDifference = [][]int{
+		{8, 6, 2, -7},
+		{-2, 1, 7, 1},
+		{3, -6, -9, 6},
+	}
+
+	markets := []outrights.Market{
+		{Name: "Winner", Payoff: "1|2x0"},
+		{Name: "Bottom", Payoff: "2x0|1"},
+	}
+	if err := outrights.InitMarkets([]string{"A", "B", "C"}, markets); err != nil {
+		t.Fatal(err)
+	}
+	return SimulationResult{SimPoints: simPoints, Markets: markets}
+}
+
+func TestMarketCorrelations(t *testing.T) {
+	result := pathsResult(t)
+	correlations, err := result.MarketCorrelations([]outrights.Market{
+		{Name: "Winner", Payoff: "1|2x0"},
+		{Name: "Bottom", Payoff: "2x0|1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"Winner: A", "Winner: A", 1},
+		{"Winner: A", "Bottom: A", -1 / math.Sqrt(3)}, // A wins on two paths and is bottom on another
+		{"Bottom: A", "Winner: A", -1 / math.Sqrt(3)},
+		{"Winner: A", "Bottom: B", 0}, // B is never bottom
+		{"Winner: B", "Winner: C", -1.0 / 3},
+	}
+	for _, tt := range tests {
+		if got := correlations[tt.a][tt.b]; math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("correlation of %s and %s = %g, want %g", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	result.SimPoints = nil
+	if _, err := result.MarketCorrelations(nil); err == nil {
+		t.Error("expected an error without RetainSimPoints")
+	}
+}
+
+func TestPortfolioStats(t *testing.T) {
+	result := pathsResult(t)
+	tests := []struct {
+		name         string
+		bets         []Bet
+		wantReturn   float64
+		wantVariance float64
+	}{
+		{"no bets", nil, 0, 0},
+		{"single winner bet", []Bet{{Market: "Winner", Team: "A", Stake: 1, Price: 2}}, 0, 1},
+		{
+			name: "winner bets hedge each other",
+			bets: []Bet{
+				{Market: "Winner", Team: "A", Stake: 1, Price: 2},
+				{Market: "Winner", Team: "B", Stake: 1, Price: 4},
+			},
+			wantReturn:   0,
+			wantVariance: 2, // Returns 0, 0, 2 and -2
+		},
+		{
+			name: "winner and bottom on the same team",
+			bets: []Bet{
+				{Market: "Winner", Team: "A", Stake: 1, Price: 2},
+				{Market: "Bottom", Team: "A", Stake: 1, Price: 5},
+			},
+			wantReturn:   0.25,
+			wantVariance: 3.1875, // Returns 0, 0, -2 and 3
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := result.PortfolioStats(tt.bets)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(stats.ExpectedReturn-tt.wantReturn) > 1e-12 || math.Abs(stats.Variance-tt.wantVariance) > 1e-12 {
+				t.Errorf("stats %+v, want return %g and variance %g", stats, tt.wantReturn, tt.wantVariance)
+			}
+			if math.Abs(stats.StdDev-math.Sqrt(stats.Variance)) > 1e-12 {
+				t.Errorf("std dev %g isn't the root of variance %g", stats.StdDev, stats.Variance)
+			}
+		})
+	}
+
+	// Errors rather than a zero-valued PortfolioStats a caller could mistake for a flat book
+	for name, bets := range map[string][]Bet{
+		"unknown market": {{Market: "Top 4", Team: "A", Stake: 1, Price: 2}},
+		"unknown team":   {{Market: "Winner", Team: "Z", Stake: 1, Price: 2}},
+	} {
+		if _, err := result.PortfolioStats(bets); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	result.SimPoints = nil
+	if _, err := result.PortfolioStats(nil); err == nil {
+		t.Error("expected an error without RetainSimPoints")
+	}
+}
+
+func TestJointProbability(t *testing.T) {
+	result := pathsResult(t)
+	aWins := Condition{Team: "A", Quantity: ConditionPosition, Value: 1}
+	bWins := Condition{Team: "B", Quantity: ConditionPosition, Value: 1}
+	aTenPoints := Condition{Team: "A", Quantity: ConditionPoints, Value: 10}
+	cPositiveGD := Condition{Team: "C", Quantity: ConditionGoalDifference, Value: 0}
+	bTopTwo := Condition{Team: "B", Quantity: ConditionPosition, Value: 2}
+
+	tests := []struct {
+		name       string
+		conditions []Condition
+		want       float64
+	}{
+		{"no conditions", nil, 1},
+		{"single condition", []Condition{aWins}, 0.5},
+		{"implied condition", []Condition{aWins, aTenPoints}, 0.5},
+		{"exclusive conditions", []Condition{aWins, bWins}, 0},
+		{"independent conditions", []Condition{aWins, cPositiveGD}, 0.25}, // 0.5 * 0.5
+		{"three conditions", []Condition{aWins, cPositiveGD, bTopTwo}, 0.25},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			joint, err := result.JointProbability(tt.conditions)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(joint-tt.want) > 1e-12 {
+				t.Errorf("joint probability %g, want %g", joint, tt.want)
+			}
+			for _, condition := range tt.conditions {
+				marginal, _ := result.JointProbability([]Condition{condition})
+				if joint > marginal {
+					t.Errorf("joint %g above the marginal %g of %+v", joint, marginal, condition)
+				}
+			}
+		})
+	}
+
+	for name, condition := range map[string]Condition{
+		"unknown team":     {Team: "Z", Quantity: ConditionPoints, Value: 1},
+		"unknown quantity": {Team: "A", Quantity: "wins", Value: 1},
+	} {
+		if _, err := result.JointProbability([]Condition{condition}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	result.SimPoints = nil
+	if _, err := result.JointProbability([]Condition{aWins}); err == nil {
+		t.Error("expected an error without RetainSimPoints")
+	}
+}
+
+func TestMiniLeague(t *testing.T) {
+	result := pathsResult(t)
+	tests := []struct {
+		name  string
+		teams []string
+		want  map[string][]float64
+	}{
+		{"A and C", []string{"A", "C"}, map[string][]float64{"A": {0.75, 0.25}, "C": {0.25, 0.75}}},
+		{"B and C", []string{"B", "C"}, map[string][]float64{"B": {0.75, 0.25}, "C": {0.25, 0.75}}},
+		{"whole league", []string{"A", "B", "C"}, map[string][]float64{
+			"A": {0.5, 0.25, 0.25},
+			"B": {0.25, 0.75, 0},
+			"C": {0.25, 0, 0.75},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := result.MiniLeague(tt.teams)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d teams, want %d", len(got), len(tt.want))
+			}
+			for team, want := range tt.want {
+				for position, prob := range want {
+					if math.Abs(got[team][position]-prob) > 1e-12 {
+						t.Errorf("%s position %d = %g, want %g", team, position+1, got[team][position], prob)
+					}
+				}
+			}
+		})
+	}
+
+	result.SimPoints = nil
+	if got := result.MiniLeague([]string{"A", "B"}); len(got) != 0 {
+		t.Errorf("without RetainSimPoints got %v, want an empty map", got)
+	}
+}
+
+func TestDiffResults(t *testing.T) {
+	before := SimulationResult{OutrightMarks: []outrights.OutrightMark{
+		{Market: "Winner", Team: "A", Mark: 0.5},
+		{Market: "Winner", Team: "B", Mark: 0.25},
+		{Market: "Bottom", Team: "C", Mark: 0.75},
+	}}
+	after := SimulationResult{OutrightMarks: []outrights.OutrightMark{
+		{Market: "Winner", Team: "A", Mark: 0.25},
+		{Market: "Winner", Team: "B", Mark: 0.5},
+		{Market: "Winner", Team: "C", Mark: 0.125},
+	}}
+
+	// Largest moves first, with equal moves left in first-seen order
+	want := []MarkDelta{
+		{Market: "Bottom", Team: "C", Old: 0.75, New: 0, Delta: -0.75},
+		{Market: "Winner", Team: "A", Old: 0.5, New: 0.25, Delta: -0.25},
+		{Market: "Winner", Team: "B", Old: 0.25, New: 0.5, Delta: 0.25},
+		{Market: "Winner", Team: "C", Old: 0, New: 0.125, Delta: 0.125},
+	}
+	if got := DiffResults(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffResults = %+v, want %+v", got, want)
+	}
+	if got := DiffResults(SimulationResult{}, SimulationResult{}); len(got) != 0 {
+		t.Errorf("expected no deltas between empty results, got %+v", got)
+	}
+}
+
+func TestHeadToHeadExpectedGoals(t *testing.T) {
+	result := SimulationResult{RemainingFixtureOdds: []outrights.FixtureOdds{
+		{Fixture: "A vs B", Lambdas: [2]float64{1.5, 0.75}},
+		{Fixture: "B vs A", Lambdas: [2]float64{1.25, 1}},
+		{Fixture: "A vs C", Lambdas: [2]float64{2, 0.5}},
+	}}
+	tests := []struct {
+		a, b         string
+		wantA, wantB float64
+	}{
+		{"A", "B", 2.5, 2},
+		{"B", "A", 2, 2.5},
+		{"C", "A", 0.5, 2},
+		{"B", "C", 0, 0},
+		{"A", "X", 0, 0},
+	}
+	for _, tt := range tests {
+		if aGoals, bGoals := result.HeadToHeadExpectedGoals(tt.a, tt.b); aGoals != tt.wantA || bGoals != tt.wantB {
+			t.Errorf("HeadToHeadExpectedGoals(%s, %s) = %g-%g, want %g-%g", tt.a, tt.b, aGoals, bGoals, tt.wantA, tt.wantB)
+		}
+	}
+}
+
+func TestPriceFixture(t *testing.T) {
+	ratings := map[string]float64{"A": 1.6, "B": 1.1}
+	homeModel := outrights.AdditiveHomeAdvantage(0.3)
+	// Reported ratings carry a normalization shift, which pricing has to undo
+	result := SimulationResult{
+		Teams:         []outrights.Team{{Name: "A", PoissonRating: 2.1}, {Name: "B", PoissonRating: 1.6}},
+		HomeModel:     homeModel,
+		RatingsOffset: 0.5,
+		UsedOptions:   SimOptions{PriceFormat: "decimal"},
+	}
+
+	odds, err := result.PriceFixture("B", "A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := homeModel.NewTeamsScoreMatrix("B", "A", ratings).MatchOdds()
+	for i := range want {
+		if math.Abs(odds.Probabilities[i]-want[i]) > 1e-12 {
+			t.Errorf("probabilities %v, want %v", odds.Probabilities, want)
+			break
+		}
+	}
+	if odds.Fixture != "B vs A" || len(odds.Prices) != 3 {
+		t.Errorf("fixture %q with prices %v, want B vs A with three prices", odds.Fixture, odds.Prices)
+	}
+	if len(odds.CorrectScores) != outrights.DefaultN*outrights.DefaultN {
+		t.Errorf("expected a full correct score grid, got %d cells", len(odds.CorrectScores))
+	}
+
+	result.UsedOptions = SimOptions{NoDraws: true}
+	odds, err = result.PriceFixture("B", "A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if odds.Probabilities[1] != 0 || math.Abs(odds.Probabilities[0]-(want[0]+want[1]/2)) > 1e-12 {
+		t.Errorf("no-draw probabilities %v, want the draw split evenly", odds.Probabilities)
+	}
+
+	for _, pair := range [][2]string{{"A", "A"}, {"A", "X"}} {
+		if _, err := result.PriceFixture(pair[0], pair[1]); err == nil {
+			t.Errorf("expected an error pricing %s vs %s", pair[0], pair[1])
+		}
+	}
+}
+
+func TestPriceSheet(t *testing.T) {
+	result := SimulationResult{
+		Teams: []outrights.Team{
+			{Name: "A", ExpectedSeasonPoints: 80},
+			{Name: "B", ExpectedSeasonPoints: 70},
+			{Name: "C", ExpectedSeasonPoints: 40},
+		},
+		OutrightMarks: []outrights.OutrightMark{
+			{Market: "Winner", Team: "B", Mark: 0.25},
+			{Market: "Winner", Team: "C", Mark: 0},
+			{Market: "Winner", Team: "A", Mark: 0.75},
+			{Market: "Bottom", Team: "A", Mark: 0.5},
+			{Market: "Bottom", Team: "C", Mark: 0.5},
+		},
+	}
+
+	rows := result.PriceSheet(0.25)
+	want := []PriceRow{
+		{Market: "Bottom", Team: "A", ExpectedPoints: 80, Probability: 0.5, FairPrice: 2, Price: 1.6},
+		{Market: "Bottom", Team: "C", ExpectedPoints: 40, Probability: 0.5, FairPrice: 2, Price: 1.6},
+		{Market: "Winner", Team: "A", ExpectedPoints: 80, Probability: 0.75, FairPrice: 4.0 / 3, Price: 1 / 0.9375},
+		{Market: "Winner", Team: "B", ExpectedPoints: 70, Probability: 0.25, FairPrice: 4, Price: 3.2},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("expected %d rows without C's zero mark, got %+v", len(want), rows)
+	}
+	for i, row := range rows {
+		if row.Market != want[i].Market || row.Team != want[i].Team || row.ExpectedPoints != want[i].ExpectedPoints ||
+			math.Abs(row.FairPrice-want[i].FairPrice) > 1e-12 || math.Abs(row.Price-want[i].Price) > 1e-12 {
+			t.Errorf("row %d = %+v, want %+v", i, row, want[i])
+		}
+	}
+}
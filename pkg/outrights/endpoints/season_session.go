@@ -0,0 +1,123 @@
+package endpoints
+
+import (
+	"log"
+	"reflect"
+	"sync"
+
+	"github.com/jhw/go-outrights/pkg/outrights"
+)
+
+// IncrementalGenerationsDivisor scales down generations for warm-started re-solves
+const IncrementalGenerationsDivisor = 10
+
+// HomeAdvantageRefitInterval is how many warm starts pass between full re-solves that fit
+// home advantage afresh, so drift in it is picked up while most re-solves stay cheap
+const HomeAdvantageRefitInterval = 5
+
+// SeasonSession caches the last season simulation so that, in a trading loop where only
+// training-event odds tick, re-runs warm-start the solver from the previous ratings and
+// home advantage with a fraction of the generations. Every HomeAdvantageRefitInterval-th
+// re-run instead solves with full generations and home advantage free. Only the solve is
+// incremental: the Monte Carlo re-runs in full each time. Identical inputs return the
+// cached result. Results, markets, handicaps and options are fixed for the session's lifetime
+type SeasonSession struct {
+	mu              sync.Mutex
+	results         []outrights.Result
+	markets         []outrights.Market
+	handicaps       map[string]int
+	opts            SimOptions
+	lastEvents      []outrights.Event
+	lastResult      *SimulationResult
+	fullGenerations int
+	warmStarts      int // Re-solves since the first run, counting home advantage refits
+}
+
+// NewSeasonSession creates a session for repeated simulations of the same season
+func NewSeasonSession(results []outrights.Result, markets []outrights.Market, handicaps map[string]int, opts ...SimOptions) *SeasonSession {
+	session := &SeasonSession{
+		results:   results,
+		markets:   markets,
+		handicaps: handicaps,
+	}
+	if len(opts) > 0 {
+		session.opts = opts[0]
+	}
+	return session
+}
+
+// Simulate runs the season simulation for the given training events, re-solving
+// incrementally from the previous run when one exists
+func (s *SeasonSession) Simulate(events []outrights.Event) (SimulationResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	
+	// Copy events, as SimulateSeason sorts them in place
+	eventsCopy := make([]outrights.Event, len(events))
+	copy(eventsCopy, events)
+	
+	if s.lastResult != nil && reflect.DeepEqual(eventsCopy, s.lastEvents) {
+		log.Printf("Season session: inputs unchanged, returning cached result")
+		return *s.lastResult, nil
+	}
+	
+	opts := s.opts
+	if s.lastResult != nil {
+		s.warmStarts++
+		refit := s.warmStarts%HomeAdvantageRefitInterval == 0
+		opts = s.warmStartOptions(*s.lastResult, refit)
+		if refit {
+			log.Printf("Season session: odds changed, re-fitting home advantage with full generations")
+		} else {
+			log.Printf("Season session: odds changed, warm-starting solver with %d generations", opts.Generations)
+		}
+	}
+	
+	// Markets are initialized in place, so give each run its own copy
+	markets := make([]outrights.Market, len(s.markets))
+	copy(markets, s.markets)
+	
+	result, err := SimulateSeason(s.results, eventsCopy, markets, s.handicaps, opts)
+	if err != nil {
+		return SimulationResult{}, err
+	}
+	
+	if s.fullGenerations == 0 {
+		s.fullGenerations = result.UsedOptions.Generations
+	}
+	s.lastEvents = make([]outrights.Event, len(events))
+	copy(s.lastEvents, events)
+	s.lastResult = &result
+	
+	return result, nil
+}
+
+// warmStartOptions derives options for a re-solve starting from a previous result's
+// ratings. Unless refit is set, it also holds home advantage and cuts generations
+func (s *SeasonSession) warmStartOptions(previous SimulationResult, refit bool) SimOptions {
+	opts := s.opts
+	
+	// Undo any reporting shift, so the solver restarts from the ratings the run priced with
+	initialRatings := make(map[string]float64)
+	for _, team := range previous.Teams {
+		initialRatings[team.Name] = team.PoissonRating - previous.RatingsOffset
+	}
+	opts.InitialRatings = initialRatings
+	opts.DisableLeagueTableInit = true
+	if refit {
+		return opts
+	}
+	
+	// Hold home advantage at its previous value unless the caller pinned, split or scaled it
+	if opts.FixedHomeAdvantage == nil && !opts.SplitHomeAdvantage && !opts.MultiplicativeHomeAdvantage {
+		homeAdvantage := previous.HomeAdvantage
+		opts.FixedHomeAdvantage = &homeAdvantage
+	}
+	
+	opts.Generations = s.fullGenerations / IncrementalGenerationsDivisor
+	if opts.Generations < 1 {
+		opts.Generations = 1
+	}
+	
+	return opts
+}
@@ -0,0 +1,133 @@
+package endpoints
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/jhw/go-outrights/pkg/outrights"
+)
+
+func TestWarmStartOptionsUndoesNormalization(t *testing.T) {
+	session := NewSeasonSession(nil, nil, nil, SimOptions{NormalizeRatings: true})
+	session.fullGenerations = 100
+	previous := SimulationResult{
+		Teams: []outrights.Team{
+			{Name: "A", PoissonRating: 1.9},
+			{Name: "B", PoissonRating: 1.1},
+		},
+		RatingsOffset: 0.4,
+		HomeAdvantage: 0.3,
+	}
+
+	opts := session.warmStartOptions(previous, false)
+	want := map[string]float64{"A": 1.5, "B": 0.7}
+	for name, rating := range want {
+		if math.Abs(opts.InitialRatings[name]-rating) > 1e-12 {
+			t.Errorf("%s warm-started at %g, want %g", name, opts.InitialRatings[name], rating)
+		}
+	}
+	if opts.FixedHomeAdvantage == nil || *opts.FixedHomeAdvantage != 0.3 {
+		t.Errorf("expected home advantage pinned at 0.3, got %v", opts.FixedHomeAdvantage)
+	}
+	if opts.Generations != 100/IncrementalGenerationsDivisor {
+		t.Errorf("expected %d generations, got %d", 100/IncrementalGenerationsDivisor, opts.Generations)
+	}
+}
+
+func TestNormalizeRatingsLeavesPricingUnchanged(t *testing.T) {
+	opts := SimOptions{Generations: 20, NPaths: 500, Seed: 11}
+	results, events, markets := loadENG1(t)
+	plain, err := SimulateSeason(results, events, markets, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts.NormalizeRatings = true
+	results, events, markets = loadENG1(t)
+	normalized, err := SimulateSeason(results, events, markets, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if normalized.RatingsOffset == 0 {
+		t.Error("expected a non-zero ratings offset")
+	}
+	if !reflect.DeepEqual(plain.FixtureOdds, normalized.FixtureOdds) {
+		t.Error("normalizing ratings changed fixture odds")
+	}
+	if !reflect.DeepEqual(plain.OutrightMarks, normalized.OutrightMarks) {
+		t.Error("normalizing ratings changed outright marks")
+	}
+}
+
+func TestWarmStartOptionsRefit(t *testing.T) {
+	session := NewSeasonSession(nil, nil, nil, SimOptions{Generations: 200})
+	session.fullGenerations = 200
+	previous := SimulationResult{Teams: []outrights.Team{{Name: "A", PoissonRating: 1.5}}, HomeAdvantage: 0.3}
+
+	opts := session.warmStartOptions(previous, true)
+	if opts.FixedHomeAdvantage != nil {
+		t.Errorf("expected a refit to leave home advantage free, got %g", *opts.FixedHomeAdvantage)
+	}
+	if opts.Generations != 200 {
+		t.Errorf("expected a refit to run full generations, got %d", opts.Generations)
+	}
+	if opts.InitialRatings["A"] != 1.5 {
+		t.Errorf("expected a refit to warm-start ratings, got %g", opts.InitialRatings["A"])
+	}
+}
+
+// tickEvents returns a copy of events with the first event's home price nudged by tick,
+// as a trading loop would see between runs
+func tickEvents(events []outrights.Event, tick int) []outrights.Event {
+	ticked := make([]outrights.Event, len(events))
+	copy(ticked, events)
+	prices := append([]float64(nil), ticked[0].MatchOdds.Prices...)
+	prices[0] += 0.01 * float64(tick%5+1)
+	ticked[0].MatchOdds.Prices = prices
+	return ticked
+}
+
+func TestSeasonSessionRefitSchedule(t *testing.T) {
+	results, events, markets := loadENG1(t)
+	session := NewSeasonSession(results, markets, nil, SimOptions{Generations: 20, NPaths: 200, Seed: 1})
+
+	for run := 0; run <= HomeAdvantageRefitInterval; run++ {
+		result, err := session.Simulate(tickEvents(events, run))
+		if err != nil {
+			t.Fatal(err)
+		}
+		pinned := result.UsedOptions.FixedHomeAdvantage != nil
+		wantPinned := run > 0 && run%HomeAdvantageRefitInterval != 0
+		if pinned != wantPinned {
+			t.Errorf("run %d: home advantage pinned %v, want %v", run, pinned, wantPinned)
+		}
+	}
+}
+
+func benchmarkSeasonSession(b *testing.B, incremental bool) {
+	results, events, markets := loadENG1(b)
+	opts := SimOptions{Generations: 200, NPaths: 1000, Seed: 1}
+	session := NewSeasonSession(results, markets, nil, opts)
+	if _, err := session.Simulate(events); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ticked := tickEvents(events, i)
+		var err error
+		if incremental {
+			_, err = session.Simulate(ticked)
+		} else {
+			_, err = SimulateSeason(results, ticked, append([]outrights.Market(nil), markets...), nil, opts)
+		}
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Compare with go test -run XXX -bench SeasonSession ./pkg/outrights/endpoints
+func BenchmarkSeasonSessionFull(b *testing.B)        { benchmarkSeasonSession(b, false) }
+func BenchmarkSeasonSessionIncremental(b *testing.B) { benchmarkSeasonSession(b, true) }
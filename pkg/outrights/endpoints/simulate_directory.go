@@ -0,0 +1,135 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jhw/go-outrights/pkg/outrights"
+)
+
+// League file suffixes; events fall back to the "-training-events.json" naming used by
+// the bundled fixtures, and a missing markets file means no markets
+const (
+	ResultsFileSuffix        = "-results.json"
+	EventsFileSuffix         = "-events.json"
+	TrainingEventsFileSuffix = "-training-events.json"
+	MarketsFileSuffix        = "-markets.json"
+)
+
+// SimulateDirectory simulates every league in dir, discovered from "<league>-results.json"
+// files with matching events and markets files, returning results keyed by league
+// Leagues run one at a time; see SimulateDirectoryWithWorkers
+func SimulateDirectory(dir string, opts SimOptions) (map[string]SimulationResult, error) {
+	return SimulateDirectoryWithWorkers(dir, opts, 1)
+}
+
+// SimulateDirectoryWithWorkers simulates every league in dir using up to workers leagues
+// in parallel. A failing league doesn't stop the others: successful results are always
+// returned, alongside a joined error describing every failure
+func SimulateDirectoryWithWorkers(dir string, opts SimOptions, workers int) (map[string]SimulationResult, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+ResultsFileSuffix))
+	if err != nil {
+		return nil, err
+	}
+
+	var leagues []string
+	for _, path := range paths {
+		leagues = append(leagues, strings.TrimSuffix(filepath.Base(path), ResultsFileSuffix))
+	}
+	sort.Strings(leagues)
+	if len(leagues) == 0 {
+		return nil, fmt.Errorf("no *%s files found in %s", ResultsFileSuffix, dir)
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make(map[string]SimulationResult)
+	var errs []error
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	jobs := make(chan string)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for league := range jobs {
+				log.Printf("Simulating league %s", league)
+				result, err := simulateLeagueFiles(dir, league, opts)
+
+				mu.Lock()
+				if err != nil {
+					errs = append(errs, fmt.Errorf("league %s: %v", league, err))
+				} else {
+					results[league] = result
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, league := range leagues {
+		jobs <- league
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results, errors.Join(errs...)
+}
+
+// simulateLeagueFiles loads one league's results, events and markets files and simulates it
+func simulateLeagueFiles(dir, league string, opts SimOptions) (SimulationResult, error) {
+	var results []outrights.Result
+	if err := readJSONFile(filepath.Join(dir, league+ResultsFileSuffix), &results); err != nil {
+		return SimulationResult{}, err
+	}
+
+	eventsPath := filepath.Join(dir, league+EventsFileSuffix)
+	if _, err := os.Stat(eventsPath); os.IsNotExist(err) {
+		eventsPath = filepath.Join(dir, league+TrainingEventsFileSuffix)
+	}
+	var events []outrights.Event
+	if err := readJSONFile(eventsPath, &events); err != nil {
+		return SimulationResult{}, err
+	}
+
+	var markets []outrights.Market
+	marketsPath := filepath.Join(dir, league+MarketsFileSuffix)
+	if _, err := os.Stat(marketsPath); err == nil {
+		if err := readJSONFile(marketsPath, &markets); err != nil {
+			return SimulationResult{}, err
+		}
+	}
+
+	return SimulateSeason(results, events, markets, nil, opts)
+}
+
+// LoadPriors reads a JSON file mapping team names to prior ratings, for SimOptions.PriorsFile
+func LoadPriors(path string) (map[string]float64, error) {
+	var priors map[string]float64
+	if err := readJSONFile(path, &priors); err != nil {
+		return nil, err
+	}
+	return priors, nil
+}
+
+// readJSONFile decodes the JSON file at path into v
+func readJSONFile(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("error parsing %s: %v", path, err)
+	}
+	return nil
+}
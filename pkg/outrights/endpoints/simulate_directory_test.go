@@ -0,0 +1,58 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeJSONFile(t *testing.T, path string, v interface{}) {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSimulateDirectory(t *testing.T) {
+	if _, err := SimulateDirectory(t.TempDir(), SimOptions{}); err == nil {
+		t.Error("expected an error for a directory without results files")
+	}
+
+	dir := t.TempDir()
+	results, events, markets := smallSeason()
+	writeJSONFile(t, filepath.Join(dir, "AAA"+ResultsFileSuffix), results)
+	writeJSONFile(t, filepath.Join(dir, "AAA"+EventsFileSuffix), events)
+	writeJSONFile(t, filepath.Join(dir, "AAA"+MarketsFileSuffix), markets)
+	// No markets file, and events under the training-events name
+	writeJSONFile(t, filepath.Join(dir, "BBB"+ResultsFileSuffix), results)
+	writeJSONFile(t, filepath.Join(dir, "BBB"+TrainingEventsFileSuffix), events)
+	if err := os.WriteFile(filepath.Join(dir, "CCC"+ResultsFileSuffix), []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, workers := range []int{1, 2} {
+		opts := SimOptions{Generations: 20, NPaths: 200, Seed: 1}
+		leagues, err := SimulateDirectoryWithWorkers(dir, opts, workers)
+		if err == nil || !strings.Contains(err.Error(), "league CCC") {
+			t.Errorf("workers %d: expected an error naming league CCC, got %v", workers, err)
+		}
+		if len(leagues) != 2 {
+			t.Fatalf("workers %d: expected results for AAA and BBB despite CCC failing, got %d", workers, len(leagues))
+		}
+		if got := len(leagues["AAA"].OutrightMarks); got != 4 {
+			t.Errorf("workers %d: AAA has %d Winner marks, want 4", workers, got)
+		}
+		if got := len(leagues["BBB"].OutrightMarks); got != 0 {
+			t.Errorf("workers %d: BBB has %d marks without a markets file", workers, got)
+		}
+		if got := len(leagues["BBB"].Teams); got != 4 {
+			t.Errorf("workers %d: BBB has %d teams, want 4", workers, got)
+		}
+	}
+}
@@ -3,11 +3,25 @@ package endpoints
 import (
 	"errors"
 	"fmt"
+	"log"
+	"math/rand"
 	"sort"
+	"time"
 	
 	"github.com/jhw/go-outrights/pkg/outrights"
 )
 
+// PointsCheckSigma is the number of Monte Carlo standard errors allowed between simulated
+// and deterministic expected season points before a team is flagged
+const PointsCheckSigma = 5.0
+
+// Path budgeting: a pilot run of BudgetPilotPaths paths times the simulation, and each
+// simulated path stores points, goal difference, goals for and wins (8 bytes each) per team
+const (
+	BudgetPilotPaths    = 100
+	simPathBytesPerTeam = 4 * 8
+)
+
 // SimOptions holds optional configuration for Simulate
 type SimOptions struct {
 	Generations          int
@@ -22,14 +36,89 @@ type SimOptions struct {
 	DecayExponent        float64
 	MutationProbability  float64
 	Debug                bool
+	SurvivalSpots        int // Number of teams relegated; 0 means nobody goes down
+	QualificationBands   map[string][2]int // Named 1-based inclusive position bands, e.g. {"UCL": {1, 4}}
+	PointsRule           outrights.PointsRule `json:"-"` // Simulated points per scoreline; nil means 3/1/0
+	NoDraws              bool    // Competition resolves every game, e.g. via shootouts
+	ShootoutHomeShare    *float64 // Share of draw mass awarded to the home side when NoDraws is set; nil uses 0.5
+	MinOverround         float64 // Lower bound of the accepted training-event overround band
+	MaxOverround         float64 // Upper bound of the band; 0 disables rejection
+	MarginMethod         string  // Margin removal method: "proportional" (default), "shin" or "odds_ratio"
+	TrackTieBreaks       bool    // Report how often goal difference decided each team's position
+	PairingRounds        map[string]int // Per-fixture ("Home vs Away") round counts overriding Rounds
+	RetainSimPoints      bool    // Keep per-path simulation data on the result for post-hoc queries
+	FixedHomeAdvantage   *float64 // Pin home advantage so the solver only fits ratings; nil fits it jointly
+	TieBreakers          []string // Ordered keys ranking teams level on points, in the current table and the sim; default goal difference
+	CutoffDate           string   // Simulate from the state on this date (inclusive); later results become remaining fixtures
+	InitialRatings       map[string]float64 // Warm-start ratings; teams not listed start at 1.0
+	DisableLeagueTableInit bool     // Keep initial ratings rather than re-initializing them from results
+	PriorsFile           string   // JSON file of team to prior rating, e.g. pre-season power ratings, seeding the solver in place of results-based init; InitialRatings take precedence
+	PriorWeight          float64  // Weight of the penalty pulling ratings toward PriorsFile's; 0 uses the priors only to seed
+	Restarts             int      // Run the genetic algorithm this many times in parallel and keep the best fit; 0 or 1 runs once
+	SplitHomeAdvantage   bool     // Fit separate home and away lambda multipliers instead of additive home advantage
+	MultiplicativeHomeAdvantage bool // Fit a home lambda multiplier (home lambda = rating * factor) instead of additive home advantage
+	DryRun               bool     // Validate inputs and report the resolved configuration without solving or simulating
+	ExcludeEvents        []string // Event names or dates to drop from the training set, e.g. fixtures with known-bad odds
+	TrainingWindow       int      // Train on only the most recent N events; 0 uses every event
+	MinTeamEvents        int      // With TrainingWindow, pull older events until each team appears this many times
+	DrawInflation        float64  // Post-hoc scale applied to fixture draw probabilities; default 1 (no change)
+	WinsorizeSigma       float64  // Pull fitted ratings beyond this many std devs from the mean back to it; 0 disables
+	ExpectedOnly         bool     // Project the final table from deterministic expected values, skipping Monte Carlo
+	FixtureWindow        int      // Simulate only each team's next N scheduled games, from unplayed results or those after CutoffDate, so points and marks cover that window; teams with none scheduled fall back to remaining fixtures, and short windows are warned about; 0 simulates the rest of the season
+	MarkFloor            float64  // Drop outright marks below this probability from the result; 0 keeps all
+	RejectDuplicateResults bool   // Error on results repeating a (home, away, date) rather than dropping them with a warning
+	FormHalfLife         float64  // Seed ratings from a form table where results halve in weight every N games; 0 disables
+	Deterministic        bool     // Play every fixture's most likely scoreline instead of sampling, for debugging
+	MaxMemoryMB          int      // Cap NPaths so per-path simulation data fits in this many MB; 0 disables
+	MaxSimulationMillis  int      // Cap NPaths so the simulation runs in roughly this many milliseconds; 0 disables
+	XGWeight             float64  // Weight of the lambda vs observed xG error for events carrying xG; 0 trains on odds only
+	RegisterMarketTeams  bool     // Add teams named only in markets, e.g. pre-season, at their initial rating
+	Seed                 int64    // Seed the run's own random source for a reproducible run; 0 leaves it unseeded
+	OutcomeWeights       []float64 // Weights of the home, draw and away terms in the 1X2 training error; nil weights them equally
+	PriceFormat          string   // Also write fair 1X2 prices on fixture odds as "decimal", "american" or "fractional"; "" omits them
+	NormalizeRatings     bool     // Report Poisson ratings shifted to a fixed mean for cross-run comparison; pricing is unaffected
+	PromotionSpots       int      // Number of teams promoted; sizes the generated Promotion market
+	GenerateBandMarkets  bool     // Add Promotion and Relegation markets sized by PromotionSpots and SurvivalSpots
+	ExactMaxTeams        int      // Leagues with at most this many teams get exact, noise-free marks and expected points without simulating; needs SharedTies, and ranks on points alone; 0 disables
+	TraceSolver          bool     // Return a structured trace of the fit: initial ratings, best candidate per generation, final fit
+	MarketPositionsOnly  bool     // Rank only the markets' teams, skipping the all-team table; teams get no position probabilities
+	SharedTies           bool     // Split teams level on points and every tie-break evenly across their positions instead of ordering them arbitrarily
+	MaxGoals             int      // Cap each side's simulated goals, folding higher scores into the cap; 0 for no cap. Analytic fixture odds are unaffected
+	RatingSigma          float64  // Std dev of a per-path Gaussian shock to each team's rating, held for the whole simulated season to model sustained over/under-performance; 0 disables
+	Rho                  *float64 // Dixon-Coles low-score correlation, in [-1, 1]; nil uses outrights.DefaultRho
+	RhoConvention        string   // What a positive Rho does: "fewer_draws" (default, Dixon-Coles' own) or "more_draws"
+}
+
+// ConfigSummary describes the resolved configuration of a simulation run
+type ConfigSummary struct {
+	Generations       int      `json:"generations"`
+	NPaths            int      `json:"n_paths"`
+	Rounds            int      `json:"rounds"`
+	TrainingEvents    int      `json:"training_events"`
+	Results           int      `json:"results"`
+	RemainingFixtures int      `json:"remaining_fixtures"`
+	Teams             []string `json:"teams"`
+	Markets           int      `json:"markets"`
 }
 
 type SimulationResult struct {
 	Teams           []outrights.Team         `json:"teams"`
 	OutrightMarks   []outrights.OutrightMark `json:"outright_marks"`
 	FixtureOdds     []outrights.FixtureOdds  `json:"fixture_odds"`
+	NPaths          int                      `json:"n_paths"` // Paths actually simulated, after any budget caps
+	RemainingFixtureOdds []outrights.FixtureOdds `json:"remaining_fixture_odds"` // Scheduled remaining games, in simulation order
 	HomeAdvantage   float64        `json:"home_advantage"`
+	HomeModel       outrights.HomeAdvantageModel `json:"home_model"`
 	SolverError     float64        `json:"solver_error"`
+	OverroundIssues []outrights.OverroundIssue `json:"overround_issues,omitempty"`
+	PointsChecks    []outrights.PointsCheck    `json:"points_checks"`
+	SimPoints       *outrights.SimPoints `json:"-"` // Per-path simulation data, only set with RetainSimPoints
+	Markets         []outrights.Market   `json:"-"` // Initialized markets, only set with RetainSimPoints
+	Config          *ConfigSummary       `json:"config,omitempty"` // Resolved configuration, only set with DryRun
+	RatingsOffset   float64              `json:"ratings_offset,omitempty"` // Added to reported Poisson ratings, only set with NormalizeRatings
+	SolverTrace     []outrights.SolverTraceEntry `json:"solver_trace,omitempty"` // Step-by-step fit, only set with TraceSolver
+	UsedOptions     SimOptions           `json:"used_options"` // Options after defaults were applied
+	Warnings        []Warning            `json:"warnings,omitempty"` // Non-fatal data issues, e.g. dropped results or under-covered teams
 }
 
 type SimulationRequest struct {
@@ -38,6 +127,7 @@ type SimulationRequest struct {
 	Events      []outrights.Event            `json:"events"`
 	Handicaps   map[string]int     `json:"handicaps"`
 	Markets     []outrights.Market           `json:"markets"`
+	MarketOnlyTeams []string                 `json:"market_only_teams,omitempty"` // Teams absent from results, kept at their initial rating
 	
 	// Solver parameters
 	PopulationSize        int     `json:"population_size"`
@@ -48,7 +138,61 @@ type SimulationRequest struct {
 	DecayExponent         float64 `json:"decay_exponent"`
 	MutationProbability   float64 `json:"mutation_probability"`
 	NPaths                int     `json:"n_paths"`
+	MaxMemoryMB           int     `json:"max_memory_mb"`
+	MaxSimulationMillis   int     `json:"max_simulation_millis"`
 	TimePowerWeighting    float64 `json:"time_power_weighting"`
+	FixedHomeAdvantage    *float64 `json:"fixed_home_advantage,omitempty"`
+	DisableLeagueTableInit bool    `json:"disable_league_table_init"`
+	PriorRatings          map[string]float64 `json:"prior_ratings,omitempty"`
+	PriorWeight           float64 `json:"prior_weight"`
+	Restarts              int     `json:"restarts"`
+	SplitHomeAdvantage    bool    `json:"split_home_advantage"`
+	MultiplicativeHomeAdvantage bool `json:"multiplicative_home_advantage"`
+	FormHalfLife          float64 `json:"form_half_life"`
+	XGWeight              float64 `json:"xg_weight"`
+	OutcomeWeights        []float64 `json:"outcome_weights,omitempty"`
+	PriceFormat           string  `json:"price_format,omitempty"`
+	NormalizeRatings      bool    `json:"normalize_ratings"`
+	PromotionSpots        int     `json:"promotion_spots"`
+	GenerateBandMarkets   bool    `json:"generate_band_markets"`
+	ExactMaxTeams         int     `json:"exact_max_teams"`
+	TraceSolver           bool    `json:"trace_solver"`
+	MarketPositionsOnly   bool    `json:"market_positions_only"`
+	SharedTies            bool    `json:"shared_ties"`
+	MaxGoals              int     `json:"max_goals"`
+	RatingSigma           float64 `json:"rating_sigma"`
+	Rho                   *float64 `json:"rho,omitempty"` // In the fewer_draws convention; nil uses outrights.DefaultRho
+	DryRun                bool    `json:"dry_run"`
+	ExpectedOnly          bool    `json:"expected_only"`
+	FixtureWindow         int     `json:"fixture_window"`
+	FixtureSchedule       []string `json:"fixture_schedule,omitempty"` // Upcoming fixtures in date order, for FixtureWindow
+	
+	// Reporting parameters
+	SurvivalSpots         int     `json:"survival_spots"`
+	QualificationBands    map[string][2]int `json:"qualification_bands,omitempty"`
+	TrackTieBreaks        bool    `json:"track_tie_breaks"`
+	RetainSimPoints       bool    `json:"retain_sim_points"`
+	
+	// Simulation parameters
+	PointsRule            outrights.PointsRule `json:"-"`
+	PairingRounds         map[string]int `json:"pairing_rounds,omitempty"`
+	TieBreakers           []string `json:"tie_breakers,omitempty"`
+	NoDraws               bool    `json:"no_draws"`
+	ShootoutHomeShare     float64 `json:"shootout_home_share"`
+	Deterministic         bool    `json:"deterministic"`
+	MarkFloor             float64 `json:"mark_floor"`
+	DrawInflation         float64 `json:"draw_inflation"`
+	
+	// Input validation parameters
+	MinOverround          float64 `json:"min_overround"`
+	MaxOverround          float64 `json:"max_overround"`
+	MarginMethod          string  `json:"margin_method"`
+	TrainingWindow        int     `json:"training_window"`
+	WinsorizeSigma        float64 `json:"winsorize_sigma"`
+	MinTeamEvents         int     `json:"min_team_events"`
+	
+	// Seed for the run's random source; 0 leaves it unseeded
+	Seed                  int64   `json:"seed"`
 }
 
 
@@ -67,6 +211,52 @@ func SimulateSeason(results []outrights.Result, events []outrights.Event, market
 	decayExponent := 0.5
 	mutationProbability := 0.1
 	debug := false
+	survivalSpots := 0
+	var qualificationBands map[string][2]int
+	var pointsRule outrights.PointsRule
+	noDraws := false
+	shootoutHomeShare := 0.5
+	minOverround := 0.0
+	maxOverround := 0.0
+	marginMethod := outrights.MarginProportional
+	trackTieBreaks := false
+	var pairingRounds map[string]int
+	retainSimPoints := false
+	var fixedHomeAdvantage *float64
+	tieBreakers := []string{outrights.TieBreakGoalDifference}
+	var initialRatings map[string]float64
+	disableLeagueTableInit := false
+	priorsFile := ""
+	priorWeight := 0.0
+	restarts := 1
+	splitHomeAdvantage := false
+	multiplicativeHomeAdvantage := false
+	dryRun := false
+	expectedOnly := false
+	fixtureWindow := 0
+	trainingWindow := 0
+	minTeamEvents := 0
+	drawInflation := 1.0
+	winsorizeSigma := 0.0
+	markFloor := 0.0
+	formHalfLife := 0.0
+	deterministic := false
+	maxMemoryMB := 0
+	maxSimulationMillis := 0
+	xgWeight := 0.0
+	var outcomeWeights []float64
+	priceFormat := ""
+	normalizeRatings := false
+	promotionSpots := 0
+	generateBandMarkets := false
+	exactMaxTeams := 0
+	traceSolver := false
+	marketPositionsOnly := false
+	sharedTies := false
+	maxGoals := 0
+	ratingSigma := 0.0
+	var rho *float64
+	rhoConvention := outrights.RhoConventionFewerDraws
 	
 	// Override with provided options
 	if len(opts) > 0 {
@@ -104,6 +294,100 @@ func SimulateSeason(results []outrights.Result, events []outrights.Event, market
 			mutationProbability = opts[0].MutationProbability
 		}
 		debug = opts[0].Debug
+		if opts[0].SurvivalSpots > 0 {
+			survivalSpots = opts[0].SurvivalSpots
+		}
+		qualificationBands = opts[0].QualificationBands
+		pointsRule = opts[0].PointsRule
+		noDraws = opts[0].NoDraws
+		if opts[0].ShootoutHomeShare != nil {
+			shootoutHomeShare = *opts[0].ShootoutHomeShare
+		}
+		if opts[0].MinOverround > 0 {
+			minOverround = opts[0].MinOverround
+		}
+		if opts[0].MaxOverround > 0 {
+			maxOverround = opts[0].MaxOverround
+		}
+		if opts[0].MarginMethod != "" {
+			marginMethod = opts[0].MarginMethod
+		}
+		trackTieBreaks = opts[0].TrackTieBreaks
+		pairingRounds = opts[0].PairingRounds
+		retainSimPoints = opts[0].RetainSimPoints
+		fixedHomeAdvantage = opts[0].FixedHomeAdvantage
+		if len(opts[0].TieBreakers) > 0 {
+			tieBreakers = opts[0].TieBreakers
+		}
+		initialRatings = opts[0].InitialRatings
+		disableLeagueTableInit = opts[0].DisableLeagueTableInit
+		priorsFile = opts[0].PriorsFile
+		if opts[0].PriorWeight > 0 {
+			priorWeight = opts[0].PriorWeight
+		}
+		if opts[0].Restarts > 0 {
+			restarts = opts[0].Restarts
+		}
+		splitHomeAdvantage = opts[0].SplitHomeAdvantage
+		multiplicativeHomeAdvantage = opts[0].MultiplicativeHomeAdvantage
+		dryRun = opts[0].DryRun
+		expectedOnly = opts[0].ExpectedOnly
+		if opts[0].FixtureWindow > 0 {
+			fixtureWindow = opts[0].FixtureWindow
+		}
+		deterministic = opts[0].Deterministic
+		normalizeRatings = opts[0].NormalizeRatings
+		generateBandMarkets = opts[0].GenerateBandMarkets
+		traceSolver = opts[0].TraceSolver
+		marketPositionsOnly = opts[0].MarketPositionsOnly
+		sharedTies = opts[0].SharedTies
+		if opts[0].MaxGoals > 0 {
+			maxGoals = opts[0].MaxGoals
+		}
+		if opts[0].RatingSigma > 0 {
+			ratingSigma = opts[0].RatingSigma
+		}
+		rho = opts[0].Rho
+		if opts[0].RhoConvention != "" {
+			rhoConvention = opts[0].RhoConvention
+		}
+		if opts[0].PromotionSpots > 0 {
+			promotionSpots = opts[0].PromotionSpots
+		}
+		if opts[0].ExactMaxTeams > 0 {
+			exactMaxTeams = opts[0].ExactMaxTeams
+		}
+		if opts[0].TrainingWindow > 0 {
+			trainingWindow = opts[0].TrainingWindow
+		}
+		if opts[0].MinTeamEvents > 0 {
+			minTeamEvents = opts[0].MinTeamEvents
+		}
+		if opts[0].DrawInflation > 0 {
+			drawInflation = opts[0].DrawInflation
+		}
+		if opts[0].WinsorizeSigma > 0 {
+			winsorizeSigma = opts[0].WinsorizeSigma
+		}
+		if opts[0].MarkFloor > 0 {
+			markFloor = opts[0].MarkFloor
+		}
+		if opts[0].FormHalfLife > 0 {
+			formHalfLife = opts[0].FormHalfLife
+		}
+		if opts[0].MaxMemoryMB > 0 {
+			maxMemoryMB = opts[0].MaxMemoryMB
+		}
+		if opts[0].MaxSimulationMillis > 0 {
+			maxSimulationMillis = opts[0].MaxSimulationMillis
+		}
+		if opts[0].XGWeight > 0 {
+			xgWeight = opts[0].XGWeight
+		}
+		outcomeWeights = opts[0].OutcomeWeights
+		if opts[0].PriceFormat != "" {
+			priceFormat = opts[0].PriceFormat
+		}
 	}
 	
 	// Validate that events are not empty
@@ -111,17 +395,68 @@ func SimulateSeason(results []outrights.Result, events []outrights.Event, market
 		return SimulationResult{}, errors.New("events cannot be empty")
 	}
 	
+	if outcomeWeights != nil {
+		if len(outcomeWeights) != 3 {
+			return SimulationResult{}, fmt.Errorf("outcome weights must have 3 entries (home, draw, away), got %d", len(outcomeWeights))
+		}
+		totalWeight := 0.0
+		for _, weight := range outcomeWeights {
+			if weight < 0 {
+				return SimulationResult{}, fmt.Errorf("outcome weights cannot be negative, got %v", outcomeWeights)
+			}
+			totalWeight += weight
+		}
+		if totalWeight == 0 {
+			return SimulationResult{}, errors.New("outcome weights cannot all be zero")
+		}
+	}
+	
 	if len(results) == 0 {
 		return SimulationResult{}, errors.New("results cannot be empty")
 	}
 	
-	// Extract team names from results
+	var warnings []Warning
+	
+	// Drop results listed more than once, which would otherwise be double-counted
+	results, duplicates := outrights.DedupeResults(results)
+	if len(duplicates) > 0 {
+		if len(opts) > 0 && opts[0].RejectDuplicateResults {
+			return SimulationResult{}, fmt.Errorf("results contain %d duplicates, first %s on %s", 
+				len(duplicates), duplicates[0].Name, duplicates[0].Date)
+		}
+		for _, duplicate := range duplicates {
+			addWarning(&warnings, WarningDuplicateResult, "dropping duplicate result %s on %s", duplicate.Name, duplicate.Date)
+		}
+	}
+	
+	// Extract team names from results, dropping any whose teams can't be parsed
 	teamNamesMap := make(map[string]bool)
+	parsedResults := make([]outrights.Result, 0, len(results))
 	for _, result := range results {
-		homeTeam, awayTeam := outrights.ParseEventName(result.Name)
+		homeTeam, awayTeam := result.Teams()
 		if homeTeam != "" && awayTeam != "" {
 			teamNamesMap[homeTeam] = true
 			teamNamesMap[awayTeam] = true
+			parsedResults = append(parsedResults, result)
+		} else {
+			addWarning(&warnings, WarningMalformedResult, "dropping result %q on %s with unparseable teams", result.Name, result.Date)
+		}
+	}
+	results = parsedResults
+	
+	// Optionally register teams that so far only appear in markets
+	var marketOnlyTeams []string
+	if len(opts) > 0 && opts[0].RegisterMarketTeams {
+		for _, market := range markets {
+			for _, list := range [][]string{market.Teams, market.Include, market.Exclude} {
+				for _, name := range list {
+					if !teamNamesMap[name] {
+						teamNamesMap[name] = true
+						marketOnlyTeams = append(marketOnlyTeams, name)
+						log.Printf("Registering market-only team %s", name)
+					}
+				}
+			}
 		}
 	}
 	
@@ -135,6 +470,37 @@ func SimulateSeason(results []outrights.Result, events []outrights.Event, market
 		return SimulationResult{}, errors.New("no valid team names found in results")
 	}
 	
+	// Read the upcoming schedule before any rewind drops the later results
+	var fixtureSchedule []string
+	if fixtureWindow > 0 {
+		cutoffDate := ""
+		if len(opts) > 0 {
+			cutoffDate = opts[0].CutoffDate
+		}
+		fixtureSchedule = outrights.ScheduledFixtures(results, cutoffDate)
+		if len(fixtureSchedule) == 0 {
+			return SimulationResult{}, errors.New("fixture window needs scheduled fixtures: unplayed results or results after the cutoff date")
+		}
+	}
+	
+	// Rewind to the cutoff date, keeping the full team list from all results
+	if len(opts) > 0 && opts[0].CutoffDate != "" {
+		results = outrights.ResultsUpTo(results, opts[0].CutoffDate)
+		events = outrights.EventsUpTo(events, opts[0].CutoffDate)
+		log.Printf("Simulating from %s: %d results and %d training events on or before cutoff", 
+			opts[0].CutoffDate, len(results), len(events))
+	}
+	
+	// Drop known-bad events from the training set
+	if len(opts) > 0 && len(opts[0].ExcludeEvents) > 0 {
+		nEvents := len(events)
+		events = outrights.ExcludeEvents(events, opts[0].ExcludeEvents)
+		log.Printf("Excluded %d training events", nEvents-len(events))
+		if len(events) == 0 {
+			return SimulationResult{}, errors.New("no training events left after exclusions")
+		}
+	}
+	
 	// Validate handicaps keys against extracted team names
 	for teamName := range handicaps {
 		found := false
@@ -149,6 +515,83 @@ func SimulateSeason(results []outrights.Result, events []outrights.Event, market
 		}
 	}
 	
+	if priorWeight > 0 && priorsFile == "" {
+		return SimulationResult{}, errors.New("prior weight needs a priors file to pull ratings toward")
+	}
+	
+	// Seed the solver from prior ratings, keeping any explicit initial ratings on top
+	var priorRatings map[string]float64
+	if priorsFile != "" {
+		var err error
+		priorRatings, err = LoadPriors(priorsFile)
+		if err != nil {
+			return SimulationResult{}, err
+		}
+		for teamName := range priorRatings {
+			if !containsString(teamNames, teamName) {
+				return SimulationResult{}, fmt.Errorf("priors file contains unknown team: %s", teamName)
+			}
+		}
+		seeded := make(map[string]float64, len(priorRatings))
+		for teamName, rating := range priorRatings {
+			seeded[teamName] = rating
+		}
+		for teamName, rating := range initialRatings {
+			seeded[teamName] = rating
+		}
+		initialRatings = seeded
+		disableLeagueTableInit = true
+		log.Printf("Loaded %d prior ratings from %s", len(priorRatings), priorsFile)
+	}
+	
+	// Validate initial ratings keys against extracted team names
+	for teamName := range initialRatings {
+		if !containsString(teamNames, teamName) {
+			return SimulationResult{}, fmt.Errorf("initial ratings contains unknown team: %s", teamName)
+		}
+	}
+	
+	// Validate home advantage options don't conflict
+	if splitHomeAdvantage && fixedHomeAdvantage != nil {
+		return SimulationResult{}, errors.New("cannot use both split and fixed home advantage")
+	}
+	if multiplicativeHomeAdvantage && (splitHomeAdvantage || fixedHomeAdvantage != nil) {
+		return SimulationResult{}, errors.New("cannot combine multiplicative home advantage with split or fixed home advantage")
+	}
+	
+	// Validate margin method
+	if err := outrights.ValidateMarginMethod(marginMethod); err != nil {
+		return SimulationResult{}, err
+	}
+	
+	// Validate price format
+	if err := outrights.ValidatePriceFormat(priceFormat); err != nil {
+		return SimulationResult{}, err
+	}
+	
+	// Validate rho once here, rather than as every matrix is built, and convert it to the
+	// convention score matrices use
+	var modelRho *float64
+	if _, err := outrights.ApplyRhoConvention(0, rhoConvention); err != nil {
+		return SimulationResult{}, err
+	}
+	if rho != nil {
+		if err := outrights.ValidateRho(*rho); err != nil {
+			return SimulationResult{}, err
+		}
+		converted, _ := outrights.ApplyRhoConvention(*rho, rhoConvention)
+		modelRho = &converted
+	}
+	
+	// Exact enumeration ranks teams on points alone, which matches only shared ties
+	if exactMaxTeams > 0 && !sharedTies {
+		return SimulationResult{}, errors.New("exact max teams needs shared ties, as enumeration ranks on points alone")
+	}
+	
+	if shootoutHomeShare < 0 || shootoutHomeShare > 1 {
+		return SimulationResult{}, fmt.Errorf("shootout home share must be between 0 and 1, got %g", shootoutHomeShare)
+	}
+	
 	// Sort events by date and name for consistent time-based weighting
 	sort.Slice(events, func(i, j int) bool {
 		if events[i].Date == events[j].Date {
@@ -164,6 +607,7 @@ func SimulateSeason(results []outrights.Result, events []outrights.Event, market
 		Events:          events,
 		Handicaps:       handicaps,
 		Markets:         markets,
+		MarketOnlyTeams: marketOnlyTeams,
 		PopulationSize:  populationSize,
 		MutationFactor:  mutationFactor,
 		EliteRatio:      eliteRatio,
@@ -173,36 +617,290 @@ func SimulateSeason(results []outrights.Result, events []outrights.Event, market
 		MutationProbability: mutationProbability,
 		NPaths:          npaths,
 		TimePowerWeighting: timePowerWeighting,
+		SurvivalSpots:   survivalSpots,
+		QualificationBands: qualificationBands,
+		PointsRule:      pointsRule,
+		NoDraws:         noDraws,
+		ShootoutHomeShare: shootoutHomeShare,
+		MinOverround:    minOverround,
+		MaxOverround:    maxOverround,
+		MarginMethod:    marginMethod,
+		TrackTieBreaks:  trackTieBreaks,
+		PairingRounds:   pairingRounds,
+		RetainSimPoints: retainSimPoints,
+		FixedHomeAdvantage: fixedHomeAdvantage,
+		TieBreakers:     tieBreakers,
+		DisableLeagueTableInit: disableLeagueTableInit,
+		PriorRatings:    priorRatings,
+		PriorWeight:     priorWeight,
+		Restarts:        restarts,
+		SplitHomeAdvantage: splitHomeAdvantage,
+		MultiplicativeHomeAdvantage: multiplicativeHomeAdvantage,
+		DryRun:          dryRun,
+		TrainingWindow:  trainingWindow,
+		MinTeamEvents:   minTeamEvents,
+		DrawInflation:   drawInflation,
+		WinsorizeSigma:  winsorizeSigma,
+		ExpectedOnly:    expectedOnly,
+		FixtureWindow:   fixtureWindow,
+		FixtureSchedule: fixtureSchedule,
+		MarkFloor:       markFloor,
+		FormHalfLife:    formHalfLife,
+		Deterministic:   deterministic,
+		MaxMemoryMB:     maxMemoryMB,
+		MaxSimulationMillis: maxSimulationMillis,
+		XGWeight:        xgWeight,
+		OutcomeWeights:  outcomeWeights,
+		PriceFormat:     priceFormat,
+		NormalizeRatings: normalizeRatings,
+		PromotionSpots:  promotionSpots,
+		GenerateBandMarkets: generateBandMarkets,
+		ExactMaxTeams:   exactMaxTeams,
+		TraceSolver:     traceSolver,
+		MarketPositionsOnly: marketPositionsOnly,
+		SharedTies:      sharedTies,
+		MaxGoals:        maxGoals,
+		RatingSigma:     ratingSigma,
+		Rho:             modelRho,
+	}
+	if len(opts) > 0 {
+		req.Seed = opts[0].Seed
 	}
 	
-	// Initialize ratings to 1.0 for all teams
+	// Initialize ratings to 1.0 for all teams, unless warm-started
 	for _, name := range teamNames {
 		req.Ratings[name] = 1.0
+		if rating, exists := initialRatings[name]; exists {
+			req.Ratings[name] = rating
+		}
 	}
 	
 	result, err := ProcessSimulation(req, generations, rounds, debug)
 	if err != nil {
 		return SimulationResult{}, err
 	}
+	result.Warnings = append(warnings, result.Warnings...)
+	
+	// Record the fully-resolved options for reproducibility
+	result.UsedOptions = SimOptions{
+		Generations:            generations,
+		NPaths:                 npaths,
+		Rounds:                 rounds,
+		TimePowerWeighting:     timePowerWeighting,
+		PopulationSize:         populationSize,
+		MutationFactor:         mutationFactor,
+		EliteRatio:             eliteRatio,
+		InitStd:                initStd,
+		LogInterval:            logInterval,
+		DecayExponent:          decayExponent,
+		MutationProbability:    mutationProbability,
+		Debug:                  debug,
+		SurvivalSpots:          survivalSpots,
+		QualificationBands:     qualificationBands,
+		PointsRule:             pointsRule,
+		NoDraws:                noDraws,
+		ShootoutHomeShare:      &shootoutHomeShare,
+		MinOverround:           minOverround,
+		MaxOverround:           maxOverround,
+		MarginMethod:           marginMethod,
+		TrackTieBreaks:         trackTieBreaks,
+		PairingRounds:          pairingRounds,
+		RetainSimPoints:        retainSimPoints,
+		FixedHomeAdvantage:     fixedHomeAdvantage,
+		TieBreakers:            tieBreakers,
+		InitialRatings:         initialRatings,
+		DisableLeagueTableInit: disableLeagueTableInit,
+		PriorsFile:             priorsFile,
+		PriorWeight:            priorWeight,
+		Restarts:               restarts,
+		SplitHomeAdvantage:     splitHomeAdvantage,
+		MultiplicativeHomeAdvantage: multiplicativeHomeAdvantage,
+		DryRun:                 dryRun,
+		TrainingWindow:         trainingWindow,
+		MinTeamEvents:          minTeamEvents,
+		DrawInflation:          drawInflation,
+		WinsorizeSigma:         winsorizeSigma,
+		ExpectedOnly:           expectedOnly,
+		FixtureWindow:          fixtureWindow,
+		MarkFloor:              markFloor,
+		FormHalfLife:           formHalfLife,
+		Deterministic:          deterministic,
+		MaxMemoryMB:            maxMemoryMB,
+		MaxSimulationMillis:    maxSimulationMillis,
+		XGWeight:               xgWeight,
+		OutcomeWeights:         outcomeWeights,
+		PriceFormat:            priceFormat,
+		NormalizeRatings:       normalizeRatings,
+		PromotionSpots:         promotionSpots,
+		GenerateBandMarkets:    generateBandMarkets,
+		ExactMaxTeams:          exactMaxTeams,
+		TraceSolver:            traceSolver,
+		MarketPositionsOnly:    marketPositionsOnly,
+		SharedTies:             sharedTies,
+		MaxGoals:               maxGoals,
+		RatingSigma:            ratingSigma,
+		Rho:                    rho,
+		RhoConvention:          rhoConvention,
+	}
+	if len(opts) > 0 {
+		result.UsedOptions.CutoffDate = opts[0].CutoffDate
+		result.UsedOptions.ExcludeEvents = opts[0].ExcludeEvents
+		result.UsedOptions.RejectDuplicateResults = opts[0].RejectDuplicateResults
+		result.UsedOptions.RegisterMarketTeams = opts[0].RegisterMarketTeams
+		result.UsedOptions.Seed = opts[0].Seed
+	}
+	
 	return result, nil
 }
 
 // ProcessSimulation processes a simulation request and returns results
 func ProcessSimulation(req SimulationRequest, generations int, rounds int, debug bool) (SimulationResult, error) {
+	var warnings []Warning
 	teamNames := make([]string, 0, len(req.Ratings))
 	for name := range req.Ratings {
 		teamNames = append(teamNames, name)
 	}
 	sort.Strings(teamNames)
 	
+	// Add promotion and relegation markets sized to the league's rules
+	if req.GenerateBandMarkets {
+		bandMarkets, err := outrights.BandMarkets(teamNames, req.PromotionSpots, req.SurvivalSpots)
+		if err != nil {
+			return SimulationResult{}, err
+		}
+		for _, bandMarket := range bandMarkets {
+			for _, market := range req.Markets {
+				if market.Name == bandMarket.Name {
+					return SimulationResult{}, fmt.Errorf("generated %s market clashes with a market of the same name", bandMarket.Name)
+				}
+			}
+		}
+		req.Markets = append(append([]outrights.Market{}, req.Markets...), bandMarkets...)
+	}
+	
 	// Initialize markets
 	if err := outrights.InitMarkets(teamNames, req.Markets); err != nil {
 		return SimulationResult{}, err
 	}
 	
+	// Validate per-pairing round counts reference known teams
+	for fixture, n := range req.PairingRounds {
+		homeTeam, awayTeam := outrights.ParseEventName(fixture)
+		if !containsString(teamNames, homeTeam) || !containsString(teamNames, awayTeam) || homeTeam == awayTeam {
+			return SimulationResult{}, fmt.Errorf("pairing rounds contains unknown fixture: %s", fixture)
+		}
+		if n < 0 {
+			return SimulationResult{}, fmt.Errorf("pairing rounds for %s must not be negative, got %d", fixture, n)
+		}
+	}
+	
+	// Validate tie-break keys
+	if err := outrights.ValidateTieBreakers(req.TieBreakers); err != nil {
+		return SimulationResult{}, err
+	}
+	
+	// Validate qualification bands against league size
+	if err := outrights.ValidateQualificationBands(req.QualificationBands, len(teamNames)); err != nil {
+		return SimulationResult{}, err
+	}
+	
+	// Flag (and optionally reject) training events with unsound books
+	trainingEvents, overroundIssues := outrights.CheckOverrounds(req.Events, req.MinOverround, req.MaxOverround)
+	for _, issue := range overroundIssues {
+		addWarning(&warnings, WarningOverround, "%s on %s: %s (overround %.3f, rejected %t)", 
+			issue.Event, issue.Date, issue.Reason, issue.Overround, issue.Rejected)
+	}
+	
+	// Drop training events whose teams can't be parsed, as no rating applies to them
+	parsedEvents := make([]outrights.Event, 0, len(trainingEvents))
+	for _, event := range trainingEvents {
+		homeTeam, awayTeam := event.Teams()
+		if homeTeam == "" || awayTeam == "" {
+			addWarning(&warnings, WarningMalformedEvent, "dropping training event %q on %s with unparseable teams", event.Name, event.Date)
+			continue
+		}
+		parsedEvents = append(parsedEvents, event)
+	}
+	trainingEvents = parsedEvents
+	
+	// Restrict training to a recent window, topping up under-covered teams
+	if req.TrainingWindow > 0 {
+		trainingEvents = outrights.SelectTrainingEvents(trainingEvents, req.TrainingWindow, req.MinTeamEvents)
+		log.Printf("Selected %d training events (window %d, min %d per team)", 
+			len(trainingEvents), req.TrainingWindow, req.MinTeamEvents)
+	}
+	
+	// An empty training set would solve to flat, meaningless ratings without complaint
+	if len(trainingEvents) == 0 {
+		return SimulationResult{}, fmt.Errorf("no training events left after filtering %d events by cutoff date, exclusions, overround and training window", 
+			len(req.Events))
+	}
+	
+	// Flag teams the training set barely constrains
+	minCoverage := req.MinTeamEvents
+	if minCoverage < 1 {
+		minCoverage = 1
+	}
+	coverage := make(map[string]int)
+	for _, event := range trainingEvents {
+		homeTeam, awayTeam := event.Teams()
+		coverage[homeTeam]++
+		coverage[awayTeam]++
+	}
+	sortedTeamNames := append([]string{}, teamNames...)
+	sort.Strings(sortedTeamNames)
+	for _, name := range sortedTeamNames {
+		if coverage[name] < minCoverage && !containsString(req.MarketOnlyTeams, name) {
+			addWarning(&warnings, WarningUnderCoveredTeam, "%s appears in %d training events, fewer than %d", name, coverage[name], minCoverage)
+		}
+	}
+
 	// Calculate league table and remaining fixtures
-	leagueTable := outrights.CalcLeagueTable(teamNames, req.Results, req.Handicaps)
-	remainingFixtures := outrights.CalcRemainingFixtures(teamNames, req.Results, rounds)
+	leagueTable := outrights.CalcLeagueTableWithTieBreakers(teamNames, req.Results, req.Handicaps, req.TieBreakers)
+	remainingFixtures := outrights.CalcRemainingFixturesWithRounds(teamNames, req.Results, rounds, req.PairingRounds)
+	
+	// Restrict to each team's next few scheduled games if asked
+	if req.FixtureWindow > 0 {
+		var fallbackTeams []string
+		remainingFixtures, fallbackTeams = outrights.NextFixtures(req.FixtureSchedule, remainingFixtures, req.FixtureWindow)
+		log.Printf("Fixture window: simulating %d fixtures covering each team's next %d games", 
+			len(remainingFixtures), req.FixtureWindow)
+		for _, name := range fallbackTeams {
+			addWarning(&warnings, WarningShortFixtureWindow, "%s has no scheduled fixtures, so its window uses unordered remaining fixtures", name)
+		}
+		windowGames := make(map[string]int)
+		for _, fixture := range remainingFixtures {
+			homeTeam, awayTeam := outrights.ParseEventName(fixture)
+			windowGames[homeTeam]++
+			windowGames[awayTeam]++
+		}
+		for _, name := range sortedTeamNames {
+			if windowGames[name] < req.FixtureWindow {
+				addWarning(&warnings, WarningShortFixtureWindow, "%s has %d games in the fixture window, fewer than %d", name, windowGames[name], req.FixtureWindow)
+			}
+		}
+	}
+	
+	// Stop after validation and setup, reporting what a full run would use
+	if req.DryRun {
+		log.Printf("Dry run: %d teams, %d training events, %d remaining fixtures, %d markets", 
+			len(teamNames), len(trainingEvents), len(remainingFixtures), len(req.Markets))
+		return SimulationResult{
+			Teams:           leagueTable,
+			OverroundIssues: overroundIssues,
+			Warnings:        warnings,
+			Config: &ConfigSummary{
+				Generations:       generations,
+				NPaths:            req.NPaths,
+				Rounds:            rounds,
+				TrainingEvents:    len(trainingEvents),
+				Results:           len(req.Results),
+				RemainingFixtures: len(remainingFixtures),
+				Teams:             teamNames,
+				Markets:           len(req.Markets),
+			},
+		}, nil
+	}
 	
 	// Create options map
 	options := map[string]interface{}{
@@ -215,31 +913,162 @@ func ProcessSimulation(req SimulationRequest, generations int, rounds int, debug
 		"mutation_probability":   req.MutationProbability,
 		"generations":            generations,
 		"debug":                  debug,
+		"margin_method":          req.MarginMethod,
+		"use_league_table_init":  !req.DisableLeagueTableInit,
+		"split_home_advantage":   req.SplitHomeAdvantage,
+		"multiplicative_home_advantage": req.MultiplicativeHomeAdvantage,
+		"form_half_life":         req.FormHalfLife,
+		"xg_weight":              req.XGWeight,
+		"outcome_weights":        req.OutcomeWeights,
+		"trace_solver":           req.TraceSolver,
+		"prior_ratings":          req.PriorRatings,
+		"prior_weight":           req.PriorWeight,
+		"restarts":               req.Restarts,
+	}
+	
+	// Draw separate seeds for the solver and the simulation from the run's own source, so
+	// a seeded run is reproducible even alongside concurrent runs
+	runRand := outrights.NewRand(req.Seed)
+	options["seed"] = runRand.Int63()
+	simSeed := runRand.Int63()
+	pilotSeed := runRand.Int63()
+	
+	if req.Rho != nil {
+		options["rho"] = *req.Rho
+	}
+	
+	// Pin home advantage if provided, otherwise the solver fits it jointly with ratings
+	if req.FixedHomeAdvantage != nil {
+		options["home_advantage"] = *req.FixedHomeAdvantage
+	}
+	
+	// Remember market-only teams' initial ratings, as no event constrains them
+	marketOnlyRatings := make(map[string]float64)
+	for _, name := range req.MarketOnlyTeams {
+		marketOnlyRatings[name] = req.Ratings[name]
 	}
 	
 	// Solve for ratings using events for training and results for initialization
-	solverResp := outrights.Solve(req.Events, req.Results, req.Ratings, req.TimePowerWeighting, options)
+	solverResp := outrights.Solve(trainingEvents, req.Results, req.Ratings, req.TimePowerWeighting, options)
 	
 	// Extract results
 	poissonRatings := solverResp["ratings"].(map[string]float64)
 	homeAdvantage := solverResp["home_advantage"].(float64)
+	homeModel := solverResp["home_model"].(outrights.HomeAdvantageModel)
 	solverError := solverResp["error"].(float64)
+	solverTrace := solverResp["trace"].([]outrights.SolverTraceEntry)
 	
-	// Run simulation
-	simPoints := outrights.NewSimPoints(leagueTable, req.NPaths)
+	// Restore market-only teams, which the solver can't fit without events
+	for name, rating := range marketOnlyRatings {
+		poissonRatings[name] = rating
+	}
 	
-	for _, eventName := range remainingFixtures {
-		simPoints.Simulate(eventName, poissonRatings, homeAdvantage)
+	// Rein in any ratings the optimizer pushed to extremes
+	if req.WinsorizeSigma > 0 {
+		winsorized := outrights.WinsorizeRatings(poissonRatings, req.WinsorizeSigma)
+		for _, name := range sortedTeamNames {
+			if winsorized[name] != poissonRatings[name] {
+				addWarning(&warnings, WarningClampedRating, "clamping %s rating from %.3f to %.3f", name, poissonRatings[name], winsorized[name])
+			}
+		}
+		poissonRatings = winsorized
 	}
 	
-	// Calculate position probabilities
-	// positionProbs := calcPositionProbabilities(simPoints, req.Markets)
+	// Project the final table from expected values alone if no simulation is wanted
+	if req.ExpectedOnly {
+		log.Printf("Expected table: projecting %d remaining fixtures without simulation", len(remainingFixtures))
+		expectedTable := calcExpectedTable(leagueTable, remainingFixtures, poissonRatings, homeModel)
+		return SimulationResult{
+			Teams:           expectedTable,
+			RatingsOffset:   normalizeReportedRatings(expectedTable, poissonRatings, req),
+			HomeAdvantage:   homeAdvantage,
+			HomeModel:       homeModel,
+			SolverError:     solverError,
+			SolverTrace:     solverTrace,
+			OverroundIssues: overroundIssues,
+			Warnings:        warnings,
+		}, nil
+	}
 	
 	// Calculate PPG ratings 
-	ppgRatings := calcPPGRatings(teamNames, poissonRatings, homeAdvantage)
+	ppgRatings := calcPPGRatings(teamNames, poissonRatings, homeModel)
+	
+	// Calculate expected goals per game
+	xgRatings := calcExpectedGoalsPerGame(teamNames, poissonRatings, homeModel)
+	attackStrengths, defenceStrengths := calcAttackDefenceStrengths(teamNames, poissonRatings, homeModel)
+	
+	pointsBreakdowns := outrights.CalcExpectedPointsBreakdown(leagueTable, remainingFixtures, poissonRatings, homeModel)
+	deterministicPoints := make(map[string]float64, len(pointsBreakdowns))
+	for name, breakdown := range pointsBreakdowns {
+		deterministicPoints[name] = breakdown.Total
+	}
 	
-	// Calculate expected points from the actual simulation results (not deterministic calculation)
-	expectedPoints := calculateExpectedSeasonPoints(simPoints)
+	// Small leagues can be enumerated exactly, taking the Monte Carlo noise out of the
+	// marks and skipping the simulation. Enumeration ranks on points alone, so it needs
+	// SharedTies; custom points rules, shootouts, rating shocks and per-path outputs need
+	// simulated scorelines
+	var exactProbabilities map[string]map[string][]float64
+	var expectedPoints map[string]float64
+	if len(leagueTable) <= req.ExactMaxTeams && req.SharedTies && req.PointsRule == nil && !req.NoDraws && 
+		req.RatingSigma == 0 && !req.Deterministic && !req.TrackTieBreaks && !req.RetainSimPoints {
+		var err error
+		exactProbabilities, err = outrights.ExactPositionProbabilities(leagueTable, remainingFixtures, poissonRatings, homeModel, req.Markets)
+		if err != nil {
+			addWarning(&warnings, WarningExactFallback, "falling back to simulated positions: %v", err)
+		} else {
+			expectedPoints = deterministicPoints
+		}
+	}
+	
+	var simPoints *outrights.SimPoints
+	var pointsChecks []outrights.PointsCheck
+	nPaths := 0
+	if exactProbabilities == nil {
+		// Shrink the path count to fit any memory or time budget
+		nPaths = req.NPaths
+		if req.MaxMemoryMB > 0 {
+			memoryPaths := req.MaxMemoryMB * 1024 * 1024 / (len(leagueTable) * simPathBytesPerTeam)
+			if memoryPaths < nPaths {
+				addWarning(&warnings, WarningPathsCapped, "capping paths at %d to fit %d MB", memoryPaths, req.MaxMemoryMB)
+				nPaths = memoryPaths
+			}
+		}
+		if req.MaxSimulationMillis > 0 {
+			timePaths := pathsWithinTime(req, leagueTable, remainingFixtures, poissonRatings, homeModel, outrights.NewRand(pilotSeed))
+			if timePaths < nPaths {
+				addWarning(&warnings, WarningPathsCapped, "capping paths at %d to run in %d ms", timePaths, req.MaxSimulationMillis)
+				nPaths = timePaths
+			}
+		}
+		if nPaths < 1 {
+			nPaths = 1
+		}
+		
+		// Run simulation
+		simPoints = newRequestSimPoints(req, leagueTable, nPaths, outrights.NewRand(simSeed))
+		for _, eventName := range remainingFixtures {
+			simPoints.SimulateWithModel(eventName, poissonRatings, homeModel)
+		}
+		
+		// Calculate expected points from the actual simulation results (not deterministic calculation)
+		expectedPoints = calculateExpectedSeasonPoints(simPoints)
+		
+		// Guard against the simulation drifting from the deterministic points model
+		// (skipped in deterministic mode, where modal scorelines don't reproduce expected points,
+		// and under rating shocks, which shift each fixture's expected points by design)
+		if !req.Deterministic && req.RatingSigma == 0 {
+			pointsChecks = simPoints.CheckExpectedPoints(deterministicPoints, PointsCheckSigma)
+			for _, check := range pointsChecks {
+				if check.Flagged {
+					addWarning(&warnings, WarningPointsCheck, "expected points check failed for %s: simulated %.3f vs deterministic %.3f (tolerance %.3f)", 
+						check.Team, check.Simulated, check.Deterministic, check.Tolerance)
+				}
+			}
+		}
+	}
+	
+	// Calculate average opponent rating over each team's remaining fixtures
+	scheduleStrength := outrights.CalcRemainingScheduleStrength(remainingFixtures, poissonRatings)
 	
 	// Update league table with ratings and expected points
 	for i := range leagueTable {
@@ -248,11 +1077,18 @@ func ProcessSimulation(req SimulationRequest, generations int, rounds int, debug
 		}
 		if expPoints, exists := expectedPoints[leagueTable[i].Name]; exists {
 			leagueTable[i].ExpectedSeasonPoints = expPoints
+			leagueTable[i].ProjectedRemainingPoints = expPoints - float64(leagueTable[i].Points)
 		}
 		if poissonRating, exists := poissonRatings[leagueTable[i].Name]; exists {
 			leagueTable[i].PoissonRating = poissonRating
 		}
-		
+		if xgRating, exists := xgRatings[leagueTable[i].Name]; exists {
+			leagueTable[i].ExpectedGoalsPerGame = xgRating
+		}
+		leagueTable[i].AttackStrength = attackStrengths[leagueTable[i].Name]
+		leagueTable[i].DefenceStrength = defenceStrengths[leagueTable[i].Name]
+		leagueTable[i].RemainingScheduleStrength = scheduleStrength[leagueTable[i].Name]
+		leagueTable[i].ExpectedPointsBreakdown = pointsBreakdowns[leagueTable[i].Name]
 	}
 	
 	// Sort teams by expected season points (descending)
@@ -261,34 +1097,212 @@ func ProcessSimulation(req SimulationRequest, generations int, rounds int, debug
 	})
 	
 	// Calculate position probabilities for markets
-	positionProbabilities := outrights.CalcPositionProbabilities(simPoints, req.Markets)
+	positionProbabilities := exactProbabilities
+	if positionProbabilities == nil {
+		positionProbabilities = outrights.CalcPositionProbabilitiesForMarkets(simPoints, req.Markets, !req.MarketPositionsOnly)
+	}
 	
 	// Assign position probabilities to teams
 	if defaultProbs, exists := positionProbabilities["default"]; exists {
 		for i := range leagueTable {
 			if teamProbs, exists := defaultProbs[leagueTable[i].Name]; exists {
 				leagueTable[i].PositionProbabilities = teamProbs
+				leagueTable[i].SurvivalProbability = outrights.CalcSurvivalProbability(teamProbs, req.SurvivalSpots)
+				if len(req.QualificationBands) > 0 {
+					leagueTable[i].QualificationProbabilities = outrights.CalcQualificationProbabilities(teamProbs, req.QualificationBands)
+				}
 			}
 		}
 	}
 	
+	// Calculate the chance of each team scoring the most league goals, which needs
+	// simulated goals
+	if simPoints != nil {
+		mostGoals := simPoints.MostGoalsProbabilities(nil)
+		for i := range leagueTable {
+			leagueTable[i].MostGoalsProbability = mostGoals[leagueTable[i].Name]
+		}
+	}
+	
+	// Optionally report how often goal difference broke a points tie
+	if req.TrackTieBreaks {
+		tieBreakRates := simPoints.GoalDifferenceTieBreaks(nil)
+		for i := range leagueTable {
+			leagueTable[i].GoalDifferenceTieBreakRate = tieBreakRates[leagueTable[i].Name]
+		}
+	}
+	
 	// Calculate outright marks
 	outrightMarks := outrights.CalcOutrightMarks(positionProbabilities, req.Markets)
+	if req.MarkFloor > 0 {
+		outrightMarks = outrights.FilterMarks(outrightMarks, req.MarkFloor)
+	}
 	
 	// Calculate fixture odds for all possible team matchups
-	fixtureOdds := outrights.CalcAllFixtureOdds(teamNames, poissonRatings, homeAdvantage)
+	fixtureOdds := outrights.CalcAllFixtureOddsWithModel(teamNames, poissonRatings, homeModel)
+	
+	// Calculate fixture odds for the scheduled remaining games driving the simulation
+	remainingFixtureOdds := make([]outrights.FixtureOdds, 0, len(remainingFixtures))
+	for _, fixture := range remainingFixtures {
+		remainingFixtureOdds = append(remainingFixtureOdds, outrights.CalcFixtureOddsWithModel(fixture, poissonRatings, homeModel))
+	}
+	
+	adjustDrawOdds(fixtureOdds, req)
+	adjustDrawOdds(remainingFixtureOdds, req)
+	if req.PriceFormat != "" {
+		for _, odds := range [][]outrights.FixtureOdds{fixtureOdds, remainingFixtureOdds} {
+			for i := range odds {
+				odds[i].Prices = outrights.FormatPrices(odds[i].Probabilities[:], req.PriceFormat)
+			}
+		}
+	}
+	
+	// Only hold on to the per-path data if asked, as it scales with teams x paths
+	var retainedSimPoints *outrights.SimPoints
+	var retainedMarkets []outrights.Market
+	if req.RetainSimPoints {
+		retainedSimPoints = simPoints
+		retainedMarkets = req.Markets
+	}
 	
 	return SimulationResult{
 		Teams:         leagueTable,
+		RatingsOffset: normalizeReportedRatings(leagueTable, poissonRatings, req),
 		OutrightMarks: outrightMarks,
 		FixtureOdds:   fixtureOdds,
+		NPaths:        nPaths,
+		RemainingFixtureOdds: remainingFixtureOdds,
 		HomeAdvantage: homeAdvantage,
+		HomeModel:     homeModel,
 		SolverError:   solverError,
+		SolverTrace:   solverTrace,
+		OverroundIssues: overroundIssues,
+		PointsChecks:    pointsChecks,
+		Warnings:        warnings,
+		SimPoints:       retainedSimPoints,
+		Markets:         retainedMarkets,
 	}, nil
 }
 
+// newRequestSimPoints creates nPaths of simulation configured as req asks, drawing from rng
+func newRequestSimPoints(req SimulationRequest, leagueTable []outrights.Team, nPaths int, rng *rand.Rand) *outrights.SimPoints {
+	simPoints := outrights.NewSimPoints(leagueTable, nPaths)
+	if req.PointsRule != nil {
+		simPoints.PointsRule = req.PointsRule
+	}
+	if len(req.TieBreakers) > 0 {
+		simPoints.TieBreakers = req.TieBreakers
+	}
+	simPoints.NoDraws = req.NoDraws
+	simPoints.ShootoutHomeShare = req.ShootoutHomeShare
+	simPoints.Deterministic = req.Deterministic
+	simPoints.SharedTies = req.SharedTies
+	simPoints.MaxGoals = req.MaxGoals
+	simPoints.RatingSigma = req.RatingSigma
+	simPoints.Rand = rng
+	return simPoints
+}
+
+// pathsWithinTime times a pilot simulation of the remaining fixtures, configured as the
+// real run and drawing from its own rng, and returns how many paths should fit in the
+// rest of req's MaxSimulationMillis budget
+func pathsWithinTime(req SimulationRequest, leagueTable []outrights.Team, remainingFixtures []string, ratings map[string]float64, homeModel outrights.HomeAdvantageModel, rng *rand.Rand) int {
+	start := time.Now()
+	pilot := newRequestSimPoints(req, leagueTable, BudgetPilotPaths, rng)
+	for _, eventName := range remainingFixtures {
+		pilot.SimulateWithModel(eventName, ratings, homeModel)
+	}
+	elapsed := time.Since(start)
+	
+	remaining := time.Duration(req.MaxSimulationMillis)*time.Millisecond - elapsed
+	perPath := elapsed / BudgetPilotPaths
+	if perPath <= 0 {
+		perPath = time.Nanosecond
+	}
+	return int(remaining / perPath)
+}
+
+// adjustDrawOdds folds the draw bucket into the win outcomes if the competition has no
+// draws, otherwise applies any league-specific draw inflation
+func adjustDrawOdds(fixtureOdds []outrights.FixtureOdds, req SimulationRequest) {
+	for i := range fixtureOdds {
+		var probs []float64
+		if req.NoDraws {
+			probs = outrights.RedistributeDraw(fixtureOdds[i].Probabilities[:], req.ShootoutHomeShare)
+		} else if req.DrawInflation > 0 && req.DrawInflation != 1.0 {
+			probs = outrights.InflateDraw(fixtureOdds[i].Probabilities[:], req.DrawInflation)
+		} else {
+			return
+		}
+		fixtureOdds[i].Probabilities = [3]float64{probs[0], probs[1], probs[2]}
+	}
+}
+
+// normalizeReportedRatings shifts each team's reported Poisson rating so the fitted
+// ratings average NormalizedRatingMean, returning the shift; a no-op returning 0 unless
+// NormalizeRatings is set. Pricing has already used the unshifted ratings
+func normalizeReportedRatings(teams []outrights.Team, ratings map[string]float64, req SimulationRequest) float64 {
+	if !req.NormalizeRatings {
+		return 0
+	}
+	_, offset := outrights.NormalizeRatings(ratings, outrights.NormalizedRatingMean)
+	for i := range teams {
+		teams[i].PoissonRating += offset
+	}
+	return offset
+}
+
+// calcExpectedTable fills in ratings and deterministic expected season points and goal
+// difference for each team, sorted by expected points then expected goal difference
+func calcExpectedTable(leagueTable []outrights.Team, remainingFixtures []string, ratings map[string]float64, homeModel outrights.HomeAdvantageModel) []outrights.Team {
+	teamNames := make([]string, len(leagueTable))
+	for i, team := range leagueTable {
+		teamNames[i] = team.Name
+	}
+	
+	ppgRatings := calcPPGRatings(teamNames, ratings, homeModel)
+	xgRatings := calcExpectedGoalsPerGame(teamNames, ratings, homeModel)
+	attackStrengths, defenceStrengths := calcAttackDefenceStrengths(teamNames, ratings, homeModel)
+	pointsBreakdowns := outrights.CalcExpectedPointsBreakdown(leagueTable, remainingFixtures, ratings, homeModel)
+	goalDifference := outrights.CalcDeterministicGoalDifference(leagueTable, remainingFixtures, ratings, homeModel)
+	scheduleStrength := outrights.CalcRemainingScheduleStrength(remainingFixtures, ratings)
+	
+	for i := range leagueTable {
+		name := leagueTable[i].Name
+		leagueTable[i].PoissonRating = ratings[name]
+		leagueTable[i].PointsPerGameRating = ppgRatings[name]
+		leagueTable[i].ExpectedGoalsPerGame = xgRatings[name]
+		leagueTable[i].AttackStrength = attackStrengths[name]
+		leagueTable[i].DefenceStrength = defenceStrengths[name]
+		leagueTable[i].ExpectedSeasonPoints = pointsBreakdowns[name].Total
+		leagueTable[i].ProjectedRemainingPoints = pointsBreakdowns[name].Total - float64(leagueTable[i].Points)
+		leagueTable[i].ExpectedPointsBreakdown = pointsBreakdowns[name]
+		leagueTable[i].ExpectedGoalDifference = goalDifference[name]
+		leagueTable[i].RemainingScheduleStrength = scheduleStrength[name]
+	}
+	
+	sort.Slice(leagueTable, func(i, j int) bool {
+		if leagueTable[i].ExpectedSeasonPoints == leagueTable[j].ExpectedSeasonPoints {
+			return leagueTable[i].ExpectedGoalDifference > leagueTable[j].ExpectedGoalDifference
+		}
+		return leagueTable[i].ExpectedSeasonPoints > leagueTable[j].ExpectedSeasonPoints
+	})
+	
+	return leagueTable
+}
+
+// containsString reports whether value is present in values
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 // calcPPGRatings calculates points per game ratings for teams based on their Poisson ratings
-func calcPPGRatings(teamNames []string, ratings map[string]float64, homeAdvantage float64) map[string]float64 {
+func calcPPGRatings(teamNames []string, ratings map[string]float64, homeModel outrights.HomeAdvantageModel) map[string]float64 {
 	ppgRatings := make(map[string]float64)
 	
 	// Initialize ratings
@@ -301,12 +1315,12 @@ func calcPPGRatings(teamNames []string, ratings map[string]float64, homeAdvantag
 		for _, awayTeam := range teamNames {
 			if homeTeam != awayTeam {
 				eventName := homeTeam + " vs " + awayTeam
-				matrix := outrights.NewScoreMatrix(eventName, ratings, homeAdvantage)
-				odds := matrix.MatchOdds()
+				matrix := homeModel.NewScoreMatrix(eventName, ratings)
 				
 				// Expected points: home wins = 3 pts, draw = 1 pt each, away win = 0/3 pts
-				ppgRatings[homeTeam] += 3*odds[0] + odds[1]  // 3*home_win + 1*draw
-				ppgRatings[awayTeam] += 3*odds[2] + odds[1]  // 3*away_win + 1*draw
+				expectedPoints := matrix.ExpectedPoints()
+				ppgRatings[homeTeam] += expectedPoints[0]
+				ppgRatings[awayTeam] += expectedPoints[1]
 			}
 		}
 	}
@@ -321,6 +1335,73 @@ func calcPPGRatings(teamNames []string, ratings map[string]float64, homeAdvantag
 	return ppgRatings
 }
 
+// calcExpectedGoalsPerGame calculates the average goals each team is expected to score
+// across a full home and away round-robin, i.e. its scoring lambda averaged over venues
+func calcExpectedGoalsPerGame(teamNames []string, ratings map[string]float64, homeModel outrights.HomeAdvantageModel) map[string]float64 {
+	xgRatings := make(map[string]float64)
+	
+	// Initialize ratings
+	for _, name := range teamNames {
+		xgRatings[name] = 0.0
+	}
+	
+	// Accumulate each side's lambda for every matchup
+	for _, homeTeam := range teamNames {
+		for _, awayTeam := range teamNames {
+			if homeTeam != awayTeam {
+				eventName := homeTeam + " vs " + awayTeam
+				matrix := homeModel.NewScoreMatrix(eventName, ratings)
+				xgRatings[homeTeam] += matrix.HomeLambda
+				xgRatings[awayTeam] += matrix.AwayLambda
+			}
+		}
+	}
+	
+	// Normalize by total number of games each team plays
+	totalGames := float64(2 * (len(teamNames) - 1))
+	for name := range xgRatings {
+		xgRatings[name] /= totalGames
+	}
+	
+	return xgRatings
+}
+
+// calcAttackDefenceStrengths calculates each team's goals scored and conceded per game
+// over a home-and-away round robin, each relative to the league mean (1.0 = average)
+func calcAttackDefenceStrengths(teamNames []string, ratings map[string]float64, homeModel outrights.HomeAdvantageModel) (map[string]float64, map[string]float64) {
+	scored := make(map[string]float64)
+	conceded := make(map[string]float64)
+	
+	for _, homeTeam := range teamNames {
+		for _, awayTeam := range teamNames {
+			if homeTeam != awayTeam {
+				homeLambda, awayLambda := homeModel.Lambdas(ratings[homeTeam], ratings[awayTeam])
+				scored[homeTeam] += homeLambda
+				conceded[homeTeam] += awayLambda
+				scored[awayTeam] += awayLambda
+				conceded[awayTeam] += homeLambda
+			}
+		}
+	}
+	
+	// Every team plays the same number of games, so totals normalize like per-game rates
+	attack := make(map[string]float64)
+	defence := make(map[string]float64)
+	meanGoals := 0.0
+	for _, name := range teamNames {
+		meanGoals += scored[name]
+	}
+	meanGoals /= float64(len(teamNames))
+	if meanGoals > 0 {
+		for _, name := range teamNames {
+			attack[name] = scored[name] / meanGoals
+			defence[name] = conceded[name] / meanGoals
+		}
+	}
+	
+	return attack, defence
+}
+
 // calculateExpectedSeasonPoints calculates expected season points from the actual simulation results
 func calculateExpectedSeasonPoints(simPoints *outrights.SimPoints) map[string]float64 {
 	teamNames, points, nPaths := simPoints.GetSimulationData()
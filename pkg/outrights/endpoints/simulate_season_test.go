@@ -0,0 +1,624 @@
+package endpoints
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/jhw/go-outrights/pkg/outrights"
+)
+
+// hasWarning reports whether warnings include one with code
+func hasWarning(warnings []Warning, code string) bool {
+	for _, warning := range warnings {
+		if warning.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func TestTimeBudgetCapsPaths(t *testing.T) {
+	results, events, markets := loadENG1(t)
+	const nPaths = 1000000
+	result, err := SimulateSeason(results, events, markets, nil, SimOptions{
+		Generations:         20,
+		NPaths:              nPaths,
+		MaxSimulationMillis: 1,
+		NoDraws:             true,
+		Seed:                3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.NPaths >= nPaths || result.NPaths < 1 {
+		t.Errorf("expected a tight budget to cap %d paths, got %d", nPaths, result.NPaths)
+	}
+	if !hasWarning(result.Warnings, WarningPathsCapped) {
+		t.Error("expected a paths_capped warning")
+	}
+}
+
+func TestTimeBudgetPilotLeavesRunUnchanged(t *testing.T) {
+	opts := SimOptions{Generations: 20, NPaths: 1000, Seed: 5}
+	results, events, markets := loadENG1(t)
+	plain, err := SimulateSeason(results, events, markets, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A budget too generous to cap still runs the pilot, which mustn't disturb the draws
+	opts.MaxSimulationMillis = 600000
+	results, events, markets = loadENG1(t)
+	budgeted, err := SimulateSeason(results, events, markets, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if budgeted.NPaths != plain.NPaths {
+		t.Fatalf("expected %d paths uncapped, got %d", plain.NPaths, budgeted.NPaths)
+	}
+	if !reflect.DeepEqual(plain.OutrightMarks, budgeted.OutrightMarks) {
+		t.Error("the timing pilot changed a seeded run's marks")
+	}
+}
+
+func TestShootoutHomeShareOption(t *testing.T) {
+	share := func(v float64) *float64 { return &v }
+	tests := []struct {
+		name    string
+		share   *float64
+		want    float64
+		wantErr bool
+	}{
+		{"nil uses the default", nil, 0.5, false},
+		{"zero is honoured", share(0), 0, false},
+		{"one is honoured", share(1), 1, false},
+		{"above one is rejected", share(1.5), 0, true},
+		{"negative is rejected", share(-0.1), 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, events, markets := loadENG1(t)
+			result, err := SimulateSeason(results, events, markets, nil, SimOptions{
+				DryRun:            true,
+				NoDraws:           true,
+				ShootoutHomeShare: tt.share,
+			})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := result.UsedOptions.ShootoutHomeShare; got == nil || *got != tt.want {
+				t.Errorf("used share %v, want %g", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPriorWeightNeedsPriorsFile(t *testing.T) {
+	results, events, markets := loadENG1(t)
+	if _, err := SimulateSeason(results, events, markets, nil, SimOptions{DryRun: true, PriorWeight: 0.5}); err == nil {
+		t.Error("expected an error for a prior weight without a priors file")
+	}
+}
+
+func TestRhoOption(t *testing.T) {
+	rho := func(v float64) *float64 { return &v }
+	results, events, markets := loadENG1(t)
+
+	for _, opts := range []SimOptions{
+		{DryRun: true, Rho: rho(2)},
+		{DryRun: true, Rho: rho(-1.5)},
+		{DryRun: true, RhoConvention: "sideways"},
+	} {
+		if _, err := SimulateSeason(results, events, markets, nil, opts); err == nil {
+			t.Errorf("expected an error for rho %v with convention %q", opts.Rho, opts.RhoConvention)
+		}
+	}
+
+	// A valid rho reaches the fitted model in the fewer_draws convention
+	result, err := SimulateSeason(results, events, markets, nil, SimOptions{
+		Generations:   5,
+		NPaths:        100,
+		Seed:          1,
+		Rho:           rho(0.2),
+		RhoConvention: "more_draws",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.HomeModel.Rho == nil || *result.HomeModel.Rho != -0.2 {
+		t.Errorf("fitted model rho %v, want -0.2", result.HomeModel.Rho)
+	}
+}
+
+// smallSeason returns a four-team double round robin with the first half played, fair
+// training events for the played games and a Winner market
+func smallSeason() ([]outrights.Result, []outrights.Event, []outrights.Market) {
+	ratings := map[string]float64{"A": 1.6, "B": 1.5, "C": 1.2, "D": 0.9}
+	homeModel := outrights.AdditiveHomeAdvantage(0.3)
+	played := []struct {
+		name  string
+		score []int
+	}{
+		{"A vs B", []int{2, 1}}, {"C vs D", []int{1, 1}}, {"A vs C", []int{0, 0}},
+		{"B vs D", []int{3, 0}}, {"A vs D", []int{2, 0}}, {"B vs C", []int{1, 0}},
+	}
+	unplayed := []string{"B vs A", "D vs C", "C vs A", "D vs B", "D vs A", "C vs B"}
+
+	var results []outrights.Result
+	var events []outrights.Event
+	for i, game := range played {
+		date := fmt.Sprintf("2024-08-%02d", 10+i)
+		results = append(results, outrights.Result{Name: game.name, Date: date, Score: game.score})
+		home, away := outrights.ParseEventName(game.name)
+		odds := homeModel.NewTeamsScoreMatrix(home, away, ratings).MatchOdds()
+		events = append(events, outrights.Event{
+			Name:      game.name,
+			Date:      date,
+			MatchOdds: outrights.MatchOdds{Prices: []float64{1 / odds[0], 1 / odds[1], 1 / odds[2]}},
+		})
+	}
+	for i, name := range unplayed {
+		results = append(results, outrights.Result{Name: name, Date: fmt.Sprintf("2024-09-%02d", 10+i)})
+	}
+	markets := []outrights.Market{{Name: "Winner", Payoff: "1|3x0"}}
+	return results, events, markets
+}
+
+func TestExactMaxTeams(t *testing.T) {
+	results, events, markets := smallSeason()
+	if _, err := SimulateSeason(results, events, markets, nil, SimOptions{DryRun: true, ExactMaxTeams: 4}); err == nil {
+		t.Error("expected an error for ExactMaxTeams without SharedTies")
+	}
+
+	opts := SimOptions{Generations: 20, NPaths: 1000, Seed: 1, ExactMaxTeams: 4, SharedTies: true}
+	exact, err := SimulateSeason(results, events, markets, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exact.NPaths != 0 {
+		t.Errorf("expected the simulation to be skipped, got %d paths", exact.NPaths)
+	}
+	if hasWarning(exact.Warnings, WarningExactFallback) {
+		t.Error("unexpected fallback to simulation")
+	}
+	total := 0.0
+	for _, mark := range exact.OutrightMarks {
+		total += mark.Mark
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("Winner marks sum to %g", total)
+	}
+
+	// Asking for per-path outputs falls back to simulating
+	opts.TrackTieBreaks = true
+	results, events, markets = smallSeason()
+	simulated, err := SimulateSeason(results, events, markets, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if simulated.NPaths != opts.NPaths {
+		t.Errorf("expected %d simulated paths with TrackTieBreaks, got %d", opts.NPaths, simulated.NPaths)
+	}
+}
+
+func TestFixtureWindowProjectsFewerPoints(t *testing.T) {
+	results, events, markets := smallSeason()
+	opts := SimOptions{Generations: 20, Seed: 1, ExpectedOnly: true}
+	full, err := SimulateSeason(results, events, markets, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.FixtureWindow = 1
+	window, err := SimulateSeason(results, events, markets, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hasWarning(window.Warnings, WarningShortFixtureWindow) {
+		t.Errorf("unexpected short window warning: %v", window.Warnings)
+	}
+
+	fullPoints := make(map[string]float64)
+	for _, team := range full.Teams {
+		fullPoints[team.Name] = team.ProjectedRemainingPoints
+	}
+	for _, team := range window.Teams {
+		if team.ProjectedRemainingPoints <= 0 || team.ProjectedRemainingPoints >= fullPoints[team.Name] {
+			t.Errorf("%s projects %.3f points over one game vs %.3f over the season",
+				team.Name, team.ProjectedRemainingPoints, fullPoints[team.Name])
+		}
+	}
+}
+
+func TestFixtureWindowWarnsOnFallback(t *testing.T) {
+	results, events, markets := smallSeason()
+	var dated []outrights.Result
+	for _, result := range results {
+		if home, _ := result.Teams(); result.Played() || home != "D" {
+			dated = append(dated, result)
+		}
+	}
+	opts := SimOptions{Generations: 20, Seed: 1, ExpectedOnly: true, FixtureWindow: 1}
+	result, err := SimulateSeason(dated, events, markets, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasWarning(result.Warnings, WarningShortFixtureWindow) {
+		t.Errorf("expected a short window warning for D, got %v", result.Warnings)
+	}
+}
+
+func TestSurvivalProbability(t *testing.T) {
+	results, events, markets := loadENG1(t)
+	result, err := SimulateSeason(results, events, markets, nil, SimOptions{
+		Generations:   100,
+		NPaths:        2000,
+		Seed:          42,
+		SurvivalSpots: 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Teams come back in expected finishing order, so the middle of the table is safe
+	teams := result.Teams
+	for _, team := range teams[len(teams)/2-2 : len(teams)/2+2] {
+		if team.SurvivalProbability < 0.95 {
+			t.Errorf("mid-table %s survives with probability %.3f, want near 1", team.Name, team.SurvivalProbability)
+		}
+	}
+	if top, bottom := teams[0], teams[len(teams)-1]; bottom.SurvivalProbability >= top.SurvivalProbability {
+		t.Errorf("bottom team %s survival %.3f not below top team %s %.3f",
+			bottom.Name, bottom.SurvivalProbability, top.Name, top.SurvivalProbability)
+	}
+}
+
+func TestAdjustDrawOdds(t *testing.T) {
+	tests := []struct {
+		name string
+		req  SimulationRequest
+		want [3]float64
+	}{
+		{"draws kept", SimulationRequest{}, [3]float64{0.5, 0.2, 0.3}},
+		{"unit inflation", SimulationRequest{DrawInflation: 1}, [3]float64{0.5, 0.2, 0.3}},
+		{"inflated draws", SimulationRequest{DrawInflation: 1.5}, [3]float64{0.4375, 0.3, 0.2625}},
+		{"no draws", SimulationRequest{NoDraws: true, ShootoutHomeShare: 0.5}, [3]float64{0.6, 0, 0.4}},
+		{"no draws wins over inflation", SimulationRequest{NoDraws: true, ShootoutHomeShare: 1, DrawInflation: 1.5}, [3]float64{0.7, 0, 0.3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixtureOdds := []outrights.FixtureOdds{
+				{Fixture: "A vs B", Probabilities: [3]float64{0.5, 0.2, 0.3}},
+				{Fixture: "B vs A", Probabilities: [3]float64{0.5, 0.2, 0.3}},
+			}
+			adjustDrawOdds(fixtureOdds, tt.req)
+			for _, odds := range fixtureOdds {
+				for i := range odds.Probabilities {
+					if math.Abs(odds.Probabilities[i]-tt.want[i]) > 1e-12 {
+						t.Errorf("%s: probabilities %v, want %v", odds.Fixture, odds.Probabilities, tt.want)
+						break
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCalcAttackDefenceStrengths(t *testing.T) {
+	teamNames := []string{"A", "B", "C"}
+	ratings := map[string]float64{"A": 1, "B": 1.5, "C": 2}
+
+	// Over a double round robin A scores 5 and concedes 8, B 7 and 7, C 9 and 6, against a
+	// league mean of 7
+	attack, defence := calcAttackDefenceStrengths(teamNames, ratings, outrights.AdditiveHomeAdvantage(0.5))
+	wantAttack := map[string]float64{"A": 5.0 / 7, "B": 1, "C": 9.0 / 7}
+	wantDefence := map[string]float64{"A": 8.0 / 7, "B": 1, "C": 6.0 / 7}
+	for _, name := range teamNames {
+		if math.Abs(attack[name]-wantAttack[name]) > 1e-12 {
+			t.Errorf("%s attack %.6f, want %.6f", name, attack[name], wantAttack[name])
+		}
+		if math.Abs(defence[name]-wantDefence[name]) > 1e-12 {
+			t.Errorf("%s defence %.6f, want %.6f", name, defence[name], wantDefence[name])
+		}
+	}
+
+	attack, defence = calcAttackDefenceStrengths(teamNames, map[string]float64{}, outrights.AdditiveHomeAdvantage(0))
+	if len(attack) != 0 || len(defence) != 0 {
+		t.Errorf("expected no strengths when no goals are expected, got %v and %v", attack, defence)
+	}
+}
+
+func TestCalcExpectedGoalsPerGame(t *testing.T) {
+	teamNames := []string{"A", "B", "C"}
+	ratings := map[string]float64{"A": 1, "B": 1.5, "C": 2}
+
+	// Each team's rating, plus half a goal of home advantage in half its games
+	want := map[string]float64{"A": 1.25, "B": 1.75, "C": 2.25}
+	got := calcExpectedGoalsPerGame(teamNames, ratings, outrights.AdditiveHomeAdvantage(0.5))
+	for _, name := range teamNames {
+		if math.Abs(got[name]-want[name]) > 1e-12 {
+			t.Errorf("%s expected goals per game %.6f, want %.6f", name, got[name], want[name])
+		}
+	}
+
+	got = calcExpectedGoalsPerGame(teamNames, ratings, outrights.MultiplicativeHomeAdvantage(1.5))
+	if want := (1.5*1 + 1) / 2; math.Abs(got["A"]-want) > 1e-12 {
+		t.Errorf("multiplicative model: A expected goals per game %.6f, want %.6f", got["A"], want)
+	}
+}
+
+func TestRetainSimPoints(t *testing.T) {
+	results, events, markets := smallSeason()
+	opts := SimOptions{Generations: 20, NPaths: 500, Seed: 1}
+	dropped, err := SimulateSeason(results, events, markets, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dropped.SimPoints != nil || dropped.Markets != nil {
+		t.Error("per-path data kept without RetainSimPoints")
+	}
+
+	opts.RetainSimPoints = true
+	retained, err := SimulateSeason(results, events, markets, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retained.SimPoints == nil || retained.SimPoints.NPaths != retained.NPaths {
+		t.Fatalf("expected %d retained paths, got %+v", retained.NPaths, retained.SimPoints)
+	}
+	if len(retained.Markets) != 1 || len(retained.Markets[0].ParsedPayoff) != 4 {
+		t.Errorf("expected the initialized Winner market, got %+v", retained.Markets)
+	}
+
+	// The retained paths reproduce the reported marks
+	winners := retained.SimPoints.PositionProbabilities(nil)
+	for _, mark := range retained.OutrightMarks {
+		if math.Abs(mark.Mark-winners[mark.Team][0]) > 1e-12 {
+			t.Errorf("%s winner mark %.4f, retained paths give %.4f", mark.Team, mark.Mark, winners[mark.Team][0])
+		}
+	}
+}
+
+func TestFixedHomeAdvantageIsHeld(t *testing.T) {
+	results, events, _ := smallSeason()
+	fixed := 0.45
+	opts := SimOptions{Generations: 20, Seed: 1, ExpectedOnly: true}
+	fitted, err := SimulateSeason(results, events, nil, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts.FixedHomeAdvantage = &fixed
+	pinned, err := SimulateSeason(results, events, nil, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pinned.HomeAdvantage != fixed || pinned.HomeModel.HomeAdvantage != fixed {
+		t.Errorf("home advantage %g (model %g), want it held at %g", pinned.HomeAdvantage, pinned.HomeModel.HomeAdvantage, fixed)
+	}
+	if fitted.HomeAdvantage == fixed {
+		t.Errorf("free fit landed exactly on %g, so the pin is untested", fixed)
+	}
+	if pinned.UsedOptions.FixedHomeAdvantage == nil || *pinned.UsedOptions.FixedHomeAdvantage != fixed {
+		t.Error("used options don't report the fixed home advantage")
+	}
+
+	opts.SplitHomeAdvantage = true
+	if _, err := SimulateSeason(results, events, nil, nil, opts); err == nil {
+		t.Error("expected an error fixing home advantage alongside a split fit")
+	}
+}
+
+func TestDisableLeagueTableInitStartsFromCallerRatings(t *testing.T) {
+	results, events, _ := smallSeason()
+	initial := map[string]float64{"A": 0.7, "B": 0.8, "C": 1.9, "D": 2.1}
+	opts := SimOptions{Generations: 5, Seed: 1, ExpectedOnly: true, TraceSolver: true, InitialRatings: initial}
+
+	// By default the initial ratings are re-estimated from results before the GA starts
+	reinitialized, err := SimulateSeason(results, events, nil, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if start := reinitialized.SolverTrace[0].Ratings; reflect.DeepEqual(start, initial) {
+		t.Errorf("GA started from the caller's ratings %v despite results-based init", start)
+	}
+
+	opts.DisableLeagueTableInit = true
+	kept, err := SimulateSeason(results, events, nil, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if start := kept.SolverTrace[0]; start.Stage != outrights.SolverTraceInitial || !reflect.DeepEqual(start.Ratings, initial) {
+		t.Errorf("GA started from %v, want the caller's ratings %v", start.Ratings, initial)
+	}
+}
+
+func TestDryRunSkipsSolveAndSimulation(t *testing.T) {
+	results, events, markets := smallSeason()
+	opts := SimOptions{Generations: 20, NPaths: 1000, Seed: 1, DryRun: true}
+	result, err := SimulateSeason(results, events, markets, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := result.Config
+	if config == nil {
+		t.Fatal("dry run returned no config")
+	}
+	teams := append([]string{}, config.Teams...)
+	sort.Strings(teams)
+	want := ConfigSummary{Generations: 20, NPaths: 1000, Rounds: 1, TrainingEvents: 6, Results: 12,
+		RemainingFixtures: 6, Teams: []string{"A", "B", "C", "D"}, Markets: 1}
+	config.Teams = teams
+	if !reflect.DeepEqual(*config, want) {
+		t.Errorf("config %+v, want %+v", *config, want)
+	}
+
+	if len(result.OutrightMarks) != 0 || len(result.FixtureOdds) != 0 || result.NPaths != 0 || result.SolverError != 0 {
+		t.Errorf("dry run solved or simulated: %d marks, %d fixture odds, %d paths, solver error %g",
+			len(result.OutrightMarks), len(result.FixtureOdds), result.NPaths, result.SolverError)
+	}
+	for _, team := range result.Teams {
+		if team.PoissonRating != 0 || team.PositionProbabilities != nil {
+			t.Errorf("%s has fitted or simulated values in a dry run: %+v", team.Name, team)
+		}
+	}
+
+	// Inputs are still validated
+	markets[0].Payoff = "1|1x0"
+	if _, err := SimulateSeason(results, events, markets, nil, opts); err == nil {
+		t.Error("expected a dry run to reject a mis-sized market")
+	}
+}
+
+func TestUsedOptionsReportsResolvedDefaults(t *testing.T) {
+	results, events, _ := smallSeason()
+	result, err := SimulateSeason(results, events, nil, nil, SimOptions{Generations: 20, Seed: 1, ExpectedOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	used := result.UsedOptions
+	if used.Generations != 20 || used.Seed != 1 || !used.ExpectedOnly {
+		t.Errorf("caller options not reported: %+v", used)
+	}
+	if used.NPaths != 5000 || used.Rounds != 1 || used.TimePowerWeighting != 1 || used.PopulationSize != 8 ||
+		used.MutationFactor != 0.1 || used.EliteRatio != 0.1 || used.InitStd != 0.2 || used.DecayExponent != 0.5 ||
+		used.MutationProbability != 0.1 || used.DrawInflation != 1 || used.Restarts != 1 {
+		t.Errorf("numeric defaults not resolved: %+v", used)
+	}
+	if used.ShootoutHomeShare == nil || *used.ShootoutHomeShare != 0.5 {
+		t.Errorf("shootout home share %v, want 0.5", used.ShootoutHomeShare)
+	}
+	if used.MarginMethod != outrights.MarginProportional || used.RhoConvention != outrights.RhoConventionFewerDraws {
+		t.Errorf("margin method %q and rho convention %q, want the defaults", used.MarginMethod, used.RhoConvention)
+	}
+	if !reflect.DeepEqual(used.TieBreakers, []string{outrights.TieBreakGoalDifference}) {
+		t.Errorf("tie breakers %v, want goal difference", used.TieBreakers)
+	}
+}
+
+func TestRegisterMarketTeams(t *testing.T) {
+	results, events, _ := smallSeason()
+	markets := []outrights.Market{{Name: "Group", Payoff: "1|1x0", Include: []string{"A", "E"}}}
+	opts := SimOptions{Generations: 20, NPaths: 200, Seed: 1}
+	if _, err := SimulateSeason(results, events, markets, nil, opts); err == nil {
+		t.Fatal("expected an error for a market naming an unknown team")
+	}
+
+	opts.RegisterMarketTeams = true
+	opts.InitialRatings = map[string]float64{"E": 0.8}
+	result, err := SimulateSeason(results, events, markets, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var registered *outrights.Team
+	for i := range result.Teams {
+		if result.Teams[i].Name == "E" {
+			registered = &result.Teams[i]
+		}
+	}
+	if registered == nil {
+		t.Fatalf("market-only team E missing from %d teams", len(result.Teams))
+	}
+	if registered.PoissonRating != 0.8 {
+		t.Errorf("E rated %.3f, want its initial rating 0.8 held", registered.PoissonRating)
+	}
+	// E has no events by design, so it isn't flagged as under-covered
+	for _, warning := range result.Warnings {
+		if warning.Code == WarningUnderCoveredTeam {
+			t.Errorf("unexpected coverage warning: %s", warning.Message)
+		}
+	}
+	if len(result.OutrightMarks) != 2 {
+		t.Errorf("expected Group marks for A and E, got %+v", result.OutrightMarks)
+	}
+}
+
+func TestProjectedRemainingPoints(t *testing.T) {
+	results, events, markets := smallSeason()
+	for _, expectedOnly := range []bool{false, true} {
+		opts := SimOptions{Generations: 20, NPaths: 500, Seed: 1, ExpectedOnly: expectedOnly}
+		result, err := SimulateSeason(results, events, markets, nil, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, team := range result.Teams {
+			if got := float64(team.Points) + team.ProjectedRemainingPoints; math.Abs(got-team.ExpectedSeasonPoints) > 1e-9 {
+				t.Errorf("expected only %t: %s has %d current + %.4f projected = %.4f, want %.4f",
+					expectedOnly, team.Name, team.Points, team.ProjectedRemainingPoints, got, team.ExpectedSeasonPoints)
+			}
+			// Three games left, each worth between zero and three points
+			if team.ProjectedRemainingPoints <= 0 || team.ProjectedRemainingPoints >= 9 {
+				t.Errorf("expected only %t: %s projects %.4f points from three games", expectedOnly, team.Name, team.ProjectedRemainingPoints)
+			}
+		}
+	}
+}
+
+func TestEmptyTrainingSetErrors(t *testing.T) {
+	results, events, markets := smallSeason()
+	var allDates []string
+	for _, event := range events {
+		allDates = append(allDates, event.Date)
+	}
+	tests := []struct {
+		name string
+		opts SimOptions
+	}{
+		{"cutoff before any events", SimOptions{CutoffDate: "2024-08-01"}},
+		{"every event excluded", SimOptions{ExcludeEvents: allDates}},
+		{"every book outside the overround band", SimOptions{MinOverround: 1.05, MaxOverround: 1.2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Generations = 20
+			tt.opts.Seed = 1
+			_, err := SimulateSeason(results, events, markets, nil, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), "no training events") {
+				t.Errorf("expected an empty training set error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDataIssuesSurfaceAsWarnings(t *testing.T) {
+	results, events, _ := smallSeason()
+	clean, err := SimulateSeason(results, events, nil, nil, SimOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clean.Warnings) != 0 {
+		t.Errorf("unexpected warnings on clean data: %v", clean.Warnings)
+	}
+
+	results = append(results,
+		outrights.Result{Name: "no fixture here", Date: "2024-09-20", Score: []int{1, 0}},
+		outrights.Result{Name: "A vs E", Date: "2024-09-21"}, // E has no training events
+		results[0],
+	)
+	events = append(events, outrights.Event{Name: "garbled", Date: "2024-08-20", MatchOdds: events[0].MatchOdds})
+	result, err := SimulateSeason(results, events, nil, nil, SimOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, code := range []string{WarningMalformedResult, WarningMalformedEvent, WarningUnderCoveredTeam, WarningDuplicateResult} {
+		if !hasWarning(result.Warnings, code) {
+			t.Errorf("missing %s warning in %v", code, result.Warnings)
+		}
+	}
+	for _, warning := range result.Warnings {
+		if warning.Code == WarningUnderCoveredTeam && !strings.HasPrefix(warning.Message, "E ") {
+			t.Errorf("coverage warning %q should only name E", warning.Message)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package endpoints
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"sync"
+
+	"github.com/jhw/go-outrights/pkg/outrights"
+)
+
+// SimulationCache stores simulation results by input hash for SimulateCached
+// Implementations must be safe for concurrent use
+type SimulationCache interface {
+	Get(key string) (SimulationResult, bool)
+	Put(key string, result SimulationResult)
+}
+
+// LRUSimulationCache is a SimulationCache holding at most capacity results, evicting the
+// least recently used when full
+type LRUSimulationCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List // Most recently used at the front
+}
+
+// lruEntry is one cached result with its key, so eviction can drop the map entry
+type lruEntry struct {
+	key    string
+	result SimulationResult
+}
+
+// NewLRUSimulationCache creates an LRU cache of the given capacity, at least one
+func NewLRUSimulationCache(capacity int) *LRUSimulationCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LRUSimulationCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get returns the result cached under key, marking it most recently used
+func (c *LRUSimulationCache) Get(key string) (SimulationResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, exists := c.entries[key]
+	if !exists {
+		return SimulationResult{}, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*lruEntry).result, true
+}
+
+// Put caches result under key, evicting the least recently used result if full
+func (c *LRUSimulationCache) Put(key string, result SimulationResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, exists := c.entries[key]; exists {
+		element.Value.(*lruEntry).result = result
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, result: result})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// SimulationCacheKey hashes the inputs to a season simulation, options and seed included,
+// into a stable hex key. PointsRule can't be hashed and is ignored
+func SimulationCacheKey(results []outrights.Result, events []outrights.Event, markets []outrights.Market, handicaps map[string]int, opts SimOptions) (string, error) {
+	data, err := json.Marshal(struct {
+		Results   []outrights.Result
+		Events    []outrights.Event
+		Markets   []outrights.Market
+		Handicaps map[string]int
+		Opts      SimOptions
+	}{results, events, markets, handicaps, opts})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// SimulateCached runs SimulateSeason through cache, returning the cached result for
+// inputs seen before. Each run draws from its own source seeded by opts.Seed, so a seeded
+// run gives the same result however many run concurrently; runs with no opts.Seed or
+// with a custom PointsRule bypass the cache. Cached results are shared between callers
+// and must not be modified
+func SimulateCached(cache SimulationCache, results []outrights.Result, events []outrights.Event, markets []outrights.Market, handicaps map[string]int, opts SimOptions) (SimulationResult, error) {
+	if opts.Seed == 0 || opts.PointsRule != nil {
+		log.Printf("Simulation cache: run is unseeded or has a custom points rule, bypassing cache")
+		return SimulateSeason(results, events, markets, handicaps, opts)
+	}
+
+	key, err := SimulationCacheKey(results, events, markets, handicaps, opts)
+	if err != nil {
+		return SimulationResult{}, err
+	}
+	if result, hit := cache.Get(key); hit {
+		log.Printf("Simulation cache: hit, returning cached result")
+		return result, nil
+	}
+
+	// Simulate on copies, as SimulateSeason sorts events and initializes markets in place,
+	// which would change the caller's key for the same inputs next time
+	result, err := SimulateSeason(results, append([]outrights.Event(nil), events...), append([]outrights.Market(nil), markets...), handicaps, opts)
+	if err != nil {
+		return SimulationResult{}, err
+	}
+	cache.Put(key, result)
+	return result, nil
+}
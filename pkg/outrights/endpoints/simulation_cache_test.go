@@ -0,0 +1,108 @@
+package endpoints
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// countingCache wraps a cache, counting hits and stores
+type countingCache struct {
+	SimulationCache
+	mu   sync.Mutex
+	hits int
+	puts int
+}
+
+func (c *countingCache) Get(key string) (SimulationResult, bool) {
+	result, hit := c.SimulationCache.Get(key)
+	if hit {
+		c.mu.Lock()
+		c.hits++
+		c.mu.Unlock()
+	}
+	return result, hit
+}
+
+func (c *countingCache) Put(key string, result SimulationResult) {
+	c.mu.Lock()
+	c.puts++
+	c.mu.Unlock()
+	c.SimulationCache.Put(key, result)
+}
+
+var cacheTestOptions = SimOptions{Generations: 20, NPaths: 500, Seed: 7}
+
+func TestSimulateCachedHit(t *testing.T) {
+	results, events, markets := loadENG1(t)
+	cache := &countingCache{SimulationCache: NewLRUSimulationCache(4)}
+
+	first, err := SimulateCached(cache, results, events, markets, nil, cacheTestOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := SimulateCached(cache, results, events, markets, nil, cacheTestOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cache.hits != 1 || cache.puts != 1 {
+		t.Errorf("expected 1 hit and 1 put, got %d hits and %d puts", cache.hits, cache.puts)
+	}
+	if !reflect.DeepEqual(first.OutrightMarks, second.OutrightMarks) {
+		t.Error("cached marks differ from the original run")
+	}
+
+	// Unseeded runs are never cached
+	unseeded := cacheTestOptions
+	unseeded.Seed = 0
+	if _, err := SimulateCached(cache, results, events, markets, nil, unseeded); err != nil {
+		t.Fatal(err)
+	}
+	if cache.hits != 1 || cache.puts != 1 {
+		t.Errorf("unseeded run touched the cache: %d hits and %d puts", cache.hits, cache.puts)
+	}
+}
+
+func TestSeededRunsReproducibleConcurrently(t *testing.T) {
+	results, events, markets := loadENG1(t)
+
+	sequential, err := SimulateSeason(results, events, markets, nil, cacheTestOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Concurrent runs, including an unseeded one drawing alongside, must match the sequential
+	// run. Each gets its own inputs, as a run sorts and initializes them in place
+	const runs = 3
+	concurrent := make([]SimulationResult, runs)
+	errs := make([]error, runs)
+	var wg sync.WaitGroup
+	for i := 0; i <= runs; i++ {
+		results, events, markets := loadENG1(t)
+		opts := cacheTestOptions
+		if i == runs {
+			opts.Seed = 0
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result, err := SimulateSeason(results, events, markets, nil, opts)
+			if i < runs {
+				concurrent[i], errs[i] = result, err
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < runs; i++ {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+		if !reflect.DeepEqual(sequential.OutrightMarks, concurrent[i].OutrightMarks) {
+			t.Errorf("concurrent run %d marks differ from the sequential run", i)
+		}
+		if !reflect.DeepEqual(sequential.Teams, concurrent[i].Teams) {
+			t.Errorf("concurrent run %d teams differ from the sequential run", i)
+		}
+	}
+}
@@ -18,16 +18,21 @@ type SolveEventsRequest struct {
 	Matches       []EventMatch           `json:"matches"`
 	HomeAdvantage float64                `json:"home_advantage"`
 	CustomOptions map[string]interface{} `json:"custom_options,omitempty"` // Optional parameter overrides
+	PriceFormat   string                 `json:"price_format,omitempty"`   // Also write fair 1X2 prices as decimal, american or fractional
 }
 
 // EventSolution represents the solution for a single event
 type EventSolution struct {
 	Fixture         string           `json:"fixture"`
 	Lambdas         [2]float64       `json:"lambdas"`          // [home_lambda, away_lambda]
+	Supremacy       float64          `json:"supremacy"`        // home_lambda - away_lambda
+	ExpectedTotal   float64          `json:"expected_total"`   // home_lambda + away_lambda
 	Probabilities   [3]float64       `json:"probabilities"`    // [home_win, draw, away_win] 
 	AsianHandicaps  [][2]interface{} `json:"asian_handicaps"`  // [(handicap, probabilities)]
 	TotalGoals      [][2]interface{} `json:"total_goals"`      // [(line, [under, over])]
+	OddEvenGoals    [2]float64       `json:"odd_even_goals"`   // [odd, even]
 	SolverError     float64          `json:"solver_error"`     // Fit quality
+	Prices          []string         `json:"prices,omitempty"` // [home_win, draw, away_win] fair prices in the requested format
 }
 
 // SolveEventsResult represents the output for solve-events workflow  
@@ -41,6 +46,9 @@ func SolveEvents(request SolveEventsRequest) (SolveEventsResult, error) {
 	if len(request.Matches) == 0 {
 		return SolveEventsResult{}, errors.New("no matches provided")
 	}
+	if err := outrights.ValidatePriceFormat(request.PriceFormat); err != nil {
+		return SolveEventsResult{}, err
+	}
 
 	var solutions []EventSolution
 
@@ -50,6 +58,9 @@ func SolveEvents(request SolveEventsRequest) (SolveEventsResult, error) {
 		if err != nil {
 			return SolveEventsResult{}, fmt.Errorf("error solving match %s: %v", match.Fixture, err)
 		}
+		if request.PriceFormat != "" {
+			solution.Prices = outrights.FormatPrices(solution.Probabilities[:], request.PriceFormat)
+		}
 		solutions = append(solutions, solution)
 	}
 
@@ -135,24 +146,24 @@ func solveIndividualMatch(match EventMatch, homeAdvantage float64, customOptions
 	homeLambda := solvedRatings[uniqueHomeTeam] + homeAdvantage
 	awayLambda := solvedRatings[uniqueAwayTeam]
 
-	// Create score matrix with solved lambdas using the existing ScoreMatrix from matrix.go
-	ratings = map[string]float64{
-		homeTeam: homeLambda - homeAdvantage, // Extract base rating
-		awayTeam: awayLambda,
-	}
-	matrix := outrights.NewScoreMatrix(match.Fixture, ratings, homeAdvantage)
+	// Create score matrix directly from the solved lambdas
+	matrix := outrights.NewScoreMatrixFromLambdas(homeLambda, awayLambda, outrights.DefaultRho, outrights.DefaultN)
 
 	// Generate comprehensive outputs using existing matrix methods
 	probabilities := matrix.MatchOdds()
 	asianHandicaps := matrix.AsianHandicaps()
 	totalGoals := matrix.TotalGoals()
+	oddEvenGoals := matrix.OddEvenGoals()
 
 	return EventSolution{
 		Fixture:        match.Fixture,
 		Lambdas:        [2]float64{homeLambda, awayLambda},
+		Supremacy:      homeLambda - awayLambda,
+		ExpectedTotal:  homeLambda + awayLambda,
 		Probabilities:  [3]float64{probabilities[0], probabilities[1], probabilities[2]},
 		AsianHandicaps: asianHandicaps,
 		TotalGoals:     totalGoals,
+		OddEvenGoals:   oddEvenGoals,
 		SolverError:    solverError,
 	}, nil
 }
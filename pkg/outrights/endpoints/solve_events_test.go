@@ -0,0 +1,42 @@
+package endpoints
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSolveEventsSupremacyAndTotal(t *testing.T) {
+	request := SolveEventsRequest{
+		Matches: []EventMatch{
+			{Fixture: "Home Favourite vs Outsider", MatchOdds: [3]float64{1.5, 4.5, 7.0}},
+			{Fixture: "Outsider vs Away Favourite", MatchOdds: [3]float64{6.0, 4.0, 1.6}},
+		},
+		HomeAdvantage: 0.3,
+		CustomOptions: map[string]interface{}{"generations": 40, "seed": int64(7)},
+	}
+	result, err := SolveEvents(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Solutions) != 2 {
+		t.Fatalf("expected a solution per match, got %d", len(result.Solutions))
+	}
+
+	for _, solution := range result.Solutions {
+		homeLambda, awayLambda := solution.Lambdas[0], solution.Lambdas[1]
+		if math.Abs(solution.Supremacy-(homeLambda-awayLambda)) > 1e-12 {
+			t.Errorf("%s: supremacy %.6f, want %.6f", solution.Fixture, solution.Supremacy, homeLambda-awayLambda)
+		}
+		if math.Abs(solution.ExpectedTotal-(homeLambda+awayLambda)) > 1e-12 {
+			t.Errorf("%s: expected total %.6f, want %.6f", solution.Fixture, solution.ExpectedTotal, homeLambda+awayLambda)
+		}
+	}
+	if result.Solutions[0].Supremacy <= 0 || result.Solutions[1].Supremacy >= 0 {
+		t.Errorf("supremacy should favour the priced favourite: %.3f and %.3f",
+			result.Solutions[0].Supremacy, result.Solutions[1].Supremacy)
+	}
+
+	if _, err := SolveEvents(SolveEventsRequest{}); err == nil {
+		t.Error("expected an error with no matches")
+	}
+}
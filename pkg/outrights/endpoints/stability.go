@@ -0,0 +1,71 @@
+package endpoints
+
+import (
+	"fmt"
+
+	"github.com/jhw/go-outrights/pkg/outrights"
+)
+
+// ParameterStability summarises the spread of one solved quantity across runs
+type ParameterStability struct {
+	Mean float64 `json:"mean"`
+	Std  float64 `json:"std"`
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+}
+
+// StabilitySummary describes how much solve-events output varies between runs
+type StabilitySummary struct {
+	Runs        int                `json:"runs"`
+	HomeLambda  ParameterStability `json:"home_lambda"`
+	AwayLambda  ParameterStability `json:"away_lambda"`
+	SolverError ParameterStability `json:"solver_error"`
+	Solutions   []EventSolution    `json:"solutions"`        // Every solution from every successful run, in run order
+	Errors      []string           `json:"errors,omitempty"` // One entry per failed run
+}
+
+// StabilityReport runs the same solve-events request runs times and reports the mean,
+// std, min and max of the solved lambdas and solver error across all solutions
+// Intended for requests of repeated identical matches, where any spread comes from the
+// genetic algorithm; a failed run is recorded in Errors and the remaining runs still go ahead
+func StabilityReport(request SolveEventsRequest, runs int) StabilitySummary {
+	var solutions []EventSolution
+	var runErrors []string
+	completed := 0
+	for i := 0; i < runs; i++ {
+		result, err := SolveEvents(request)
+		if err != nil {
+			runErrors = append(runErrors, fmt.Sprintf("run %d: %v", i+1, err))
+			continue
+		}
+		solutions = append(solutions, result.Solutions...)
+		completed++
+	}
+
+	var homeLambdas, awayLambdas, errors []float64
+	for _, solution := range solutions {
+		homeLambdas = append(homeLambdas, solution.Lambdas[0])
+		awayLambdas = append(awayLambdas, solution.Lambdas[1])
+		errors = append(errors, solution.SolverError)
+	}
+
+	return StabilitySummary{
+		Runs:        completed,
+		HomeLambda:  summariseParameter(homeLambdas),
+		AwayLambda:  summariseParameter(awayLambdas),
+		SolverError: summariseParameter(errors),
+		Solutions:   solutions,
+		Errors:      runErrors,
+	}
+}
+
+// summariseParameter calculates the mean, sample std, min and max of values
+func summariseParameter(values []float64) ParameterStability {
+	min, max := outrights.MinMax(values)
+	return ParameterStability{
+		Mean: outrights.Mean(values),
+		Std:  outrights.StdDev(values),
+		Min:  min,
+		Max:  max,
+	}
+}
@@ -0,0 +1,52 @@
+package endpoints
+
+import (
+	"math"
+	"testing"
+)
+
+func stabilityRequest(n int) SolveEventsRequest {
+	var matches []EventMatch
+	for i := 0; i < n; i++ {
+		matches = append(matches, EventMatch{
+			Fixture:   "TestTeamA vs TestTeamB",
+			MatchOdds: [3]float64{2.0, 1.0 / 0.3, 5.0},
+		})
+	}
+	return SolveEventsRequest{
+		Matches:       matches,
+		HomeAdvantage: 0.3,
+		CustomOptions: map[string]interface{}{"generations": 20, "seed": int64(7)},
+	}
+}
+
+func TestStabilityReportSeededRunsAgree(t *testing.T) {
+	summary := StabilityReport(stabilityRequest(4), 2)
+	if summary.Runs != 2 || len(summary.Solutions) != 8 {
+		t.Fatalf("got %d runs and %d solutions, want 2 and 8", summary.Runs, len(summary.Solutions))
+	}
+	if len(summary.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", summary.Errors)
+	}
+	for name, parameter := range map[string]ParameterStability{
+		"home lambda":  summary.HomeLambda,
+		"away lambda":  summary.AwayLambda,
+		"solver error": summary.SolverError,
+	} {
+		if math.Abs(parameter.Std) > 1e-12 || parameter.Min != parameter.Max {
+			t.Errorf("%s varies across seeded solves: %+v", name, parameter)
+		}
+	}
+}
+
+func TestStabilityReportCollectsRunErrors(t *testing.T) {
+	request := stabilityRequest(1)
+	request.PriceFormat = "bogus"
+	summary := StabilityReport(request, 3)
+	if summary.Runs != 0 || len(summary.Solutions) != 0 {
+		t.Fatalf("got %d runs and %d solutions from failing requests", summary.Runs, len(summary.Solutions))
+	}
+	if len(summary.Errors) != 3 {
+		t.Fatalf("got %d errors, want one per run: %v", len(summary.Errors), summary.Errors)
+	}
+}
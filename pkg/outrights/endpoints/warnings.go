@@ -0,0 +1,34 @@
+package endpoints
+
+import (
+	"fmt"
+	"log"
+)
+
+// Warning codes for the non-fatal issues collected on SimulationResult.Warnings
+const (
+	WarningDuplicateResult    = "duplicate_result"     // A result listed more than once was dropped
+	WarningMalformedResult    = "malformed_result"     // A result's teams couldn't be parsed, so it was ignored
+	WarningMalformedEvent     = "malformed_event"      // A training event's teams couldn't be parsed, so it was dropped
+	WarningOverround          = "overround"            // A training event's book looks unsound
+	WarningUnderCoveredTeam   = "under_covered_team"   // A team has too few training events to rate reliably
+	WarningClampedRating      = "clamped_rating"       // A solved rating was winsorized
+	WarningPathsCapped        = "paths_capped"         // Fewer paths were simulated to fit a memory or time budget
+	WarningPointsCheck        = "points_check"         // Simulated expected points drifted from the deterministic model
+	WarningExactFallback      = "exact_fallback"       // Exact enumeration failed, so positions were simulated
+	WarningShortFixtureWindow = "short_fixture_window" // A team has fewer than FixtureWindow games to simulate, or none scheduled
+)
+
+// Warning is a non-fatal data or configuration issue met during a simulation, for API
+// consumers to surface to users
+type Warning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// addWarning logs a non-fatal issue and records it in warnings
+func addWarning(warnings *[]Warning, code, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	log.Printf("Warning: %s", message)
+	*warnings = append(*warnings, Warning{Code: code, Message: message})
+}
@@ -0,0 +1,150 @@
+package outrights
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MaxExactStates caps the distinct points tables ExactPositionProbabilities will track
+// before giving up, keeping it to leagues small enough to enumerate
+const MaxExactStates = 1000000
+
+// exactState is one distinct set of points gained from the remaining fixtures, with
+// its probability
+type exactState struct {
+	gains       []byte
+	probability float64
+}
+
+// ExactPositionProbabilities calculates finishing position probabilities by enumerating
+// every home/draw/away outcome of the remaining fixtures, merging outcomes that give the
+// same points, so the result has no Monte Carlo noise. Wins score 3 and draws 1; goals
+// aren't modelled, so teams level on points share the positions they span equally, as
+// SimPoints does with SharedTies and no TieBreakers. The result has the same shape as CalcPositionProbabilities. Errors if the league is
+// too large to enumerate
+func ExactPositionProbabilities(leagueTable []Team, remainingFixtures []string, ratings map[string]float64, homeModel HomeAdvantageModel, markets []Market) (map[string]map[string][]float64, error) {
+	teamIndex := make(map[string]int, len(leagueTable))
+	teamNames := make([]string, len(leagueTable))
+	for i, team := range leagueTable {
+		teamIndex[team.Name] = i
+		teamNames[i] = team.Name
+	}
+
+	// Points gained are held in a byte per team
+	fixtureCounts := make([]int, len(leagueTable))
+	for _, fixture := range remainingFixtures {
+		homeTeam, awayTeam := ParseEventName(fixture)
+		for _, name := range []string{homeTeam, awayTeam} {
+			idx, exists := teamIndex[name]
+			if !exists {
+				return nil, fmt.Errorf("remaining fixture %s has unknown team %s", fixture, name)
+			}
+			fixtureCounts[idx]++
+			if 3*fixtureCounts[idx] > 255 {
+				return nil, fmt.Errorf("%s has too many remaining fixtures to enumerate", name)
+			}
+		}
+	}
+
+	// Fold in one fixture at a time, merging outcomes that leave the same points gained
+	states := map[string]float64{string(make([]byte, len(leagueTable))): 1.0}
+	for _, fixture := range remainingFixtures {
+		homeTeam, awayTeam := ParseEventName(fixture)
+		home, away := teamIndex[homeTeam], teamIndex[awayTeam]
+		odds := homeModel.NewTeamsScoreMatrix(homeTeam, awayTeam, ratings).MatchOdds()
+		outcomes := [3][2]byte{{3, 0}, {1, 1}, {0, 3}}
+
+		// Fold in a fixed order so repeated runs agree to the last bit
+		next := make(map[string]float64, len(states)*3)
+		for _, key := range sortedStateKeys(states) {
+			probability := states[key]
+			for k, points := range outcomes {
+				if odds[k] == 0 {
+					continue
+				}
+				gains := []byte(key)
+				gains[home] += points[0]
+				gains[away] += points[1]
+				next[string(gains)] += probability * odds[k]
+			}
+		}
+		if len(next) > MaxExactStates {
+			return nil, fmt.Errorf("more than %d distinct points tables; league too large to enumerate", MaxExactStates)
+		}
+		states = next
+	}
+
+	keys := sortedStateKeys(states)
+	enumerated := make([]exactState, len(keys))
+	for i, key := range keys {
+		enumerated[i] = exactState{gains: []byte(key), probability: states[key]}
+	}
+
+	positionProbs := make(map[string]map[string][]float64)
+	positionProbs["default"] = exactSubsetPositions(leagueTable, enumerated, teamNames, teamIndex)
+	for _, market := range markets {
+		if len(market.Teams) > 0 {
+			positionProbs[market.Name] = exactSubsetPositions(leagueTable, enumerated, market.Teams, teamIndex)
+		}
+	}
+
+	return positionProbs, nil
+}
+
+// sortedStateKeys returns the keys of states in sorted order
+func sortedStateKeys(states map[string]float64) []string {
+	keys := make([]string, 0, len(states))
+	for key := range states {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// exactSubsetPositions ranks the given teams by final points in every enumerated state,
+// splitting each group level on points evenly across the positions it spans
+func exactSubsetPositions(leagueTable []Team, states []exactState, teamNames []string, teamIndex map[string]int) map[string][]float64 {
+	var selected []int
+	for _, name := range teamNames {
+		if idx, exists := teamIndex[name]; exists {
+			selected = append(selected, idx)
+		}
+	}
+
+	probs := make([][]float64, len(selected))
+	for i := range probs {
+		probs[i] = make([]float64, len(selected))
+	}
+
+	order := make([]int, len(selected))
+	finalPoints := make([]int, len(selected))
+	for _, state := range states {
+		for i, idx := range selected {
+			order[i] = i
+			finalPoints[i] = leagueTable[idx].Points + int(state.gains[idx])
+		}
+		sort.Slice(order, func(a, b int) bool {
+			return finalPoints[order[a]] > finalPoints[order[b]]
+		})
+
+		for start := 0; start < len(order); {
+			end := start + 1
+			for end < len(order) && finalPoints[order[end]] == finalPoints[order[start]] {
+				end++
+			}
+			share := state.probability / float64(end-start)
+			for _, i := range order[start:end] {
+				for pos := start; pos < end; pos++ {
+					probs[i][pos] += share
+				}
+			}
+			start = end
+		}
+	}
+
+	probabilities := make(map[string][]float64, len(selected))
+	for i, idx := range selected {
+		probabilities[leagueTable[idx].Name] = probs[i]
+	}
+	return probabilities
+}
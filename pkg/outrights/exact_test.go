@@ -0,0 +1,62 @@
+package outrights
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// smallLeague returns a four-team table part way through a double round robin, with the
+// remaining fixtures and ratings to play them at
+func smallLeague() ([]Team, []string, map[string]float64) {
+	leagueTable := []Team{
+		{Name: "A", Points: 7},
+		{Name: "B", Points: 6},
+		{Name: "C", Points: 4},
+		{Name: "D", Points: 1},
+	}
+	remainingFixtures := []string{"A vs B", "C vs D", "B vs C", "D vs A", "A vs C", "B vs D"}
+	ratings := map[string]float64{"A": 1.6, "B": 1.5, "C": 1.2, "D": 0.9}
+	return leagueTable, remainingFixtures, ratings
+}
+
+func TestExactPositionProbabilitiesMatchSharedTiesSimulation(t *testing.T) {
+	leagueTable, remainingFixtures, ratings := smallLeague()
+	homeModel := AdditiveHomeAdvantage(0.3)
+
+	exact, err := ExactPositionProbabilities(leagueTable, remainingFixtures, ratings, homeModel, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := ExactPositionProbabilities(leagueTable, remainingFixtures, ratings, homeModel, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(exact, again) {
+		t.Error("repeated enumerations differ")
+	}
+
+	// Simulating with shared ties and no tie-breakers ranks on points alone, as enumeration does
+	sp := NewSimPoints(leagueTable, 50000)
+	sp.Rand = rand.New(rand.NewSource(1))
+	sp.SharedTies = true
+	sp.TieBreakers = nil
+	for _, fixture := range remainingFixtures {
+		sp.SimulateWithModel(fixture, ratings, homeModel)
+	}
+	simulated := sp.PositionProbabilities(nil)
+
+	for name, probs := range exact["default"] {
+		total := 0.0
+		for pos, probability := range probs {
+			total += probability
+			if diff := math.Abs(probability - simulated[name][pos]); diff > 0.01 {
+				t.Errorf("%s position %d: exact %.4f, simulated %.4f", name, pos+1, probability, simulated[name][pos])
+			}
+		}
+		if math.Abs(total-1) > 1e-9 {
+			t.Errorf("%s position probabilities sum to %g", name, total)
+		}
+	}
+}
@@ -2,10 +2,17 @@ package outrights
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 )
 
+// Names of the band markets generated by BandMarkets
+const (
+	PromotionMarketName  = "Promotion"
+	RelegationMarketName = "Relegation"
+)
+
 // parsePayoff parses payoff expressions like "1|4x0.25|19x0" meaning 1 winner gets 1, 4 get 0.25, 19 losers get 0
 func parsePayoff(payoffExpr string) ([]float64, error) {
 	var payoff []float64
@@ -171,6 +178,13 @@ func InitMarkets(teamNames []string, markets []Market) error {
 			return fmt.Errorf("market %s cannot have both include and exclude fields", market.Name)
 		}
 		
+		// A repeated team would be double-counted in the payoff
+		for _, list := range [][]string{market.Include, market.Exclude} {
+			if teamName, found := duplicateTeam(list); found {
+				return fmt.Errorf("%s market lists team %s more than once", market.Name, teamName)
+			}
+		}
+		
 		// Initialize teams based on include/exclude
 		var err error
 		if len(market.Include) > 0 {
@@ -189,4 +203,157 @@ func InitMarkets(teamNames []string, markets []Market) error {
 	return nil
 }
 
+// duplicateTeam returns the first team listed more than once in teams
+func duplicateTeam(teams []string) (string, bool) {
+	seen := make(map[string]bool, len(teams))
+	for _, teamName := range teams {
+		if seen[teamName] {
+			return teamName, true
+		}
+		seen[teamName] = true
+	}
+	return "", false
+}
+
+// ValidateMarkets checks all markets up front and returns every problem found, rather
+// than failing on the first as InitMarkets does. Markets are not modified
+func ValidateMarkets(teamNames []string, markets []Market) []error {
+	var errs []error
+	
+	knownTeams := make(map[string]bool)
+	for _, name := range teamNames {
+		knownTeams[name] = true
+	}
+	
+	for _, market := range markets {
+		if len(market.Include) > 0 && len(market.Exclude) > 0 {
+			errs = append(errs, fmt.Errorf("market %s cannot have both include and exclude fields", market.Name))
+		}
+		
+		for _, list := range [][]string{market.Include, market.Exclude} {
+			if teamName, found := duplicateTeam(list); found {
+				errs = append(errs, fmt.Errorf("%s market lists team %s more than once", market.Name, teamName))
+			}
+		}
+		
+		// Check for unknown teams in either list
+		for _, teamName := range append(append([]string{}, market.Include...), market.Exclude...) {
+			if !knownTeams[teamName] {
+				errs = append(errs, fmt.Errorf("%s market has unknown team %s", market.Name, teamName))
+			}
+		}
+		
+		if market.Payoff == "" {
+			errs = append(errs, fmt.Errorf("market %s has no payoff defined", market.Name))
+			continue
+		}
+		
+		parsedPayoff, err := parsePayoff(market.Payoff)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error parsing payoff for market %s: %v", market.Name, err))
+			continue
+		}
+		
+		// Validate payoff length against the market's team count
+		var expectedLength int
+		var kind string
+		if len(market.Include) > 0 {
+			expectedLength, kind = len(market.Include), "include teams"
+		} else if len(market.Exclude) > 0 {
+			expectedLength, kind = len(teamNames)-len(market.Exclude), "remaining teams"
+		} else {
+			expectedLength, kind = len(teamNames), "total teams"
+		}
+		if len(parsedPayoff) != expectedLength {
+			errs = append(errs, fmt.Errorf("%s market payoff length (%d) does not match %s count (%d)", 
+				market.Name, len(parsedPayoff), kind, expectedLength))
+		}
+	}
+	
+	return errs
+}
+
+// ExpandMarketFamily generates one market per entry in family.Positions, each paying 1 to
+// the top N of the family's teams and 0 to the rest, with payoffs sized to the selection
+func ExpandMarketFamily(family MarketFamily, teamNames []string) ([]Market, error) {
+	if len(family.Include) > 0 && len(family.Exclude) > 0 {
+		return nil, fmt.Errorf("%s market family cannot have both include and exclude fields", family.Name)
+	}
+	
+	nTeams := len(teamNames) - len(family.Exclude)
+	if len(family.Include) > 0 {
+		nTeams = len(family.Include)
+	}
+	
+	markets := make([]Market, 0, len(family.Positions))
+	for _, n := range family.Positions {
+		if n < 1 || n > nTeams {
+			return nil, fmt.Errorf("%s market family position %d out of range 1-%d", family.Name, n, nTeams)
+		}
+		payoff := fmt.Sprintf("%dx1", n)
+		if n < nTeams {
+			payoff += fmt.Sprintf("|%dx0", nTeams-n)
+		}
+		markets = append(markets, Market{
+			Name:    fmt.Sprintf("%s %d", family.Name, n),
+			Payoff:  payoff,
+			Include: family.Include,
+			Exclude: family.Exclude,
+		})
+	}
+	
+	// Catch unknown teams now rather than when the markets are simulated
+	initialized := make([]Market, len(markets))
+	copy(initialized, markets)
+	if err := InitMarkets(teamNames, initialized); err != nil {
+		return nil, err
+	}
+	
+	return markets, nil
+}
+
+// BandMarkets generates a market paying 1 to each of the top promoted teams and one
+// paying 1 to each of the bottom relegated teams, so the band sizes follow the league's
+// rules rather than hand-authored payoffs; a zero count skips that market
+func BandMarkets(teamNames []string, promoted, relegated int) ([]Market, error) {
+	nTeams := len(teamNames)
+	if promoted < 0 || relegated < 0 {
+		return nil, fmt.Errorf("promoted (%d) and relegated (%d) counts cannot be negative", promoted, relegated)
+	}
+	if promoted+relegated > nTeams {
+		return nil, fmt.Errorf("promoted (%d) plus relegated (%d) teams exceeds league size %d", promoted, relegated, nTeams)
+	}
+	
+	var markets []Market
+	if promoted > 0 {
+		payoff := fmt.Sprintf("%dx1", promoted)
+		if promoted < nTeams {
+			payoff += fmt.Sprintf("|%dx0", nTeams-promoted)
+		}
+		markets = append(markets, Market{Name: PromotionMarketName, Payoff: payoff})
+	}
+	if relegated > 0 {
+		payoff := fmt.Sprintf("%dx1", relegated)
+		if relegated < nTeams {
+			payoff = fmt.Sprintf("%dx0|", nTeams-relegated) + payoff
+		}
+		markets = append(markets, Market{Name: RelegationMarketName, Payoff: payoff})
+	}
+	return markets, nil
+}
 
+// MarketImpliedRanking orders teams by their outright winner prices, shortest first, as
+// the market's implied strength ranking; equal prices fall back to name order
+func MarketImpliedRanking(winnerPrices map[string]float64) []string {
+	ranking := make([]string, 0, len(winnerPrices))
+	for name := range winnerPrices {
+		ranking = append(ranking, name)
+	}
+	sort.Slice(ranking, func(i, j int) bool {
+		if winnerPrices[ranking[i]] != winnerPrices[ranking[j]] {
+			return winnerPrices[ranking[i]] < winnerPrices[ranking[j]]
+		}
+		return ranking[i] < ranking[j]
+	})
+	return ranking
+}
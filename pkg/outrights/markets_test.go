@@ -0,0 +1,145 @@
+package outrights
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDuplicateMarketTeams(t *testing.T) {
+	teamNames := []string{"A", "B", "C", "D"}
+	tests := []struct {
+		name    string
+		market  Market
+		wantErr bool
+	}{
+		{"distinct include", Market{Name: "Top", Payoff: "1|1x0", Include: []string{"A", "B"}}, false},
+		{"distinct exclude", Market{Name: "Rest", Payoff: "1|1x0", Exclude: []string{"A", "B"}}, false},
+		{"repeated include", Market{Name: "Top", Payoff: "1|2x0", Include: []string{"A", "B", "A"}}, true},
+		{"repeated exclude", Market{Name: "Rest", Payoff: "1|1x0", Exclude: []string{"C", "C"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateMarkets(teamNames, []Market{tt.market})
+			if gotErr := len(errs) > 0; gotErr != tt.wantErr {
+				t.Errorf("ValidateMarkets errors = %v, want error %t", errs, tt.wantErr)
+			}
+			err := InitMarkets(teamNames, []Market{tt.market})
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Errorf("InitMarkets error = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateMarketsCollectsEveryError(t *testing.T) {
+	teamNames := []string{"A", "B", "C", "D"}
+	markets := []Market{
+		{Name: "Winner", Payoff: "1|3x0"},
+		{Name: "Mixed", Include: []string{"A", "X"}, Exclude: []string{"B"}},
+		{Name: "Garbled", Payoff: "one|3x0"},
+		{Name: "Short", Payoff: "1|1x0"},
+	}
+	want := []string{
+		"market Mixed cannot have both include and exclude fields",
+		"Mixed market has unknown team X",
+		"market Mixed has no payoff defined",
+		"error parsing payoff for market Garbled",
+		"Short market payoff length (2) does not match total teams count (4)",
+	}
+
+	errs := ValidateMarkets(teamNames, markets)
+	if len(errs) != len(want) {
+		t.Fatalf("expected %d errors, got %d: %v", len(want), len(errs), errs)
+	}
+	for i, err := range errs {
+		if !strings.Contains(err.Error(), want[i]) {
+			t.Errorf("error %d = %q, want it to contain %q", i, err, want[i])
+		}
+	}
+	if markets[0].ParsedPayoff != nil {
+		t.Error("ValidateMarkets modified a market")
+	}
+}
+
+func TestExpandMarketFamily(t *testing.T) {
+	teamNames := []string{"A", "B", "C", "D"}
+	tests := []struct {
+		name    string
+		family  MarketFamily
+		want    []Market
+		wantErr bool
+	}{
+		{
+			name:   "whole league",
+			family: MarketFamily{Name: "Top", Positions: []int{1, 2, 4}},
+			want: []Market{
+				{Name: "Top 1", Payoff: "1x1|3x0"},
+				{Name: "Top 2", Payoff: "2x1|2x0"},
+				{Name: "Top 4", Payoff: "4x1"},
+			},
+		},
+		{
+			name:   "include",
+			family: MarketFamily{Name: "Best", Positions: []int{1}, Include: []string{"B", "C"}},
+			want:   []Market{{Name: "Best 1", Payoff: "1x1|1x0", Include: []string{"B", "C"}}},
+		},
+		{
+			name:   "exclude",
+			family: MarketFamily{Name: "Rest", Positions: []int{2}, Exclude: []string{"A"}},
+			want:   []Market{{Name: "Rest 2", Payoff: "2x1|1x0", Exclude: []string{"A"}}},
+		},
+		{name: "include and exclude", family: MarketFamily{Name: "Top", Positions: []int{1}, Include: []string{"A", "B"}, Exclude: []string{"C"}}, wantErr: true},
+		{name: "zero position", family: MarketFamily{Name: "Top", Positions: []int{0}}, wantErr: true},
+		{name: "position past the selection", family: MarketFamily{Name: "Rest", Positions: []int{4}, Exclude: []string{"A"}}, wantErr: true},
+		{name: "unknown team", family: MarketFamily{Name: "Top", Positions: []int{1}, Include: []string{"A", "X"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			markets, err := ExpandMarketFamily(tt.family, teamNames)
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Fatalf("ExpandMarketFamily error = %v, want error %t", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(markets, tt.want) {
+				t.Errorf("ExpandMarketFamily = %+v, want %+v", markets, tt.want)
+			}
+		})
+	}
+}
+
+func TestBandMarkets(t *testing.T) {
+	teamNames := []string{"A", "B", "C", "D", "E"}
+	tests := []struct {
+		name                string
+		promoted, relegated int
+		want                []Market
+		wantErr             bool
+	}{
+		{"both bands", 2, 1, []Market{
+			{Name: PromotionMarketName, Payoff: "2x1|3x0"},
+			{Name: RelegationMarketName, Payoff: "4x0|1x1"},
+		}, false},
+		{"promotion only", 1, 0, []Market{{Name: PromotionMarketName, Payoff: "1x1|4x0"}}, false},
+		{"relegation only", 0, 3, []Market{{Name: RelegationMarketName, Payoff: "2x0|3x1"}}, false},
+		{"neither", 0, 0, nil, false},
+		{"whole league promoted", 5, 0, []Market{{Name: PromotionMarketName, Payoff: "5x1"}}, false},
+		{"negative count", -1, 1, nil, true},
+		{"bands overlap", 3, 3, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			markets, err := BandMarkets(teamNames, tt.promoted, tt.relegated)
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Fatalf("BandMarkets error = %v, want error %t", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(markets, tt.want) {
+				t.Errorf("BandMarkets = %+v, want %+v", markets, tt.want)
+			}
+			if len(markets) > 0 {
+				if err := InitMarkets(teamNames, markets); err != nil {
+					t.Errorf("generated markets don't initialize: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -2,12 +2,26 @@ package outrights
 
 import (
 	"fmt"
+	"math"
 	"sort"
 	"strings"
 )
 
+// Methods for distributing a bookmaker margin across a market; proportional pricing
+// reuses MarginProportional
+const (
+	BookEqualMargin       = "equal_margin"
+	BookFavouriteWeighted = "favourite_weighted"
+)
+
 // calcPositionProbabilities calculates position probabilities for each market using simulation results
 func CalcPositionProbabilities(simPoints *SimPoints, markets []Market) map[string]map[string][]float64 {
+	return CalcPositionProbabilitiesForMarkets(simPoints, markets, true)
+}
+
+// CalcPositionProbabilitiesForMarkets is CalcPositionProbabilities with the all-team
+// "default" probabilities optional, so a focused query only ranks its markets' teams
+func CalcPositionProbabilitiesForMarkets(simPoints *SimPoints, markets []Market, includeDefault bool) map[string]map[string][]float64 {
 	positionProbs := make(map[string]map[string][]float64)
 	
 	// Cache to avoid duplicate calculations for same team sets
@@ -26,11 +40,13 @@ func CalcPositionProbabilities(simPoints *SimPoints, markets []Market) map[strin
 	}
 	
 	// Default probabilities for all teams
-	defaultKey := getCacheKey(nil)
-	if _, exists := cache[defaultKey]; !exists {
-		cache[defaultKey] = simPoints.positionProbabilities(nil)
+	if includeDefault {
+		defaultKey := getCacheKey(nil)
+		if _, exists := cache[defaultKey]; !exists {
+			cache[defaultKey] = simPoints.positionProbabilities(nil)
+		}
+		positionProbs["default"] = cache[defaultKey]
 	}
-	positionProbs["default"] = cache[defaultKey]
 	
 	// Market-specific probabilities
 	for _, market := range markets {
@@ -78,8 +94,87 @@ func CalcOutrightMarks(positionProbabilities map[string]map[string][]float64, ma
 	return marks
 }
 
+// FilterMarks drops marks below floor, e.g. 0.001 to hide long shots; a floor of 0
+// keeps every mark, including zeros
+func FilterMarks(marks []OutrightMark, floor float64) []OutrightMark {
+	filtered := make([]OutrightMark, 0, len(marks))
+	for _, mark := range marks {
+		if mark.Mark >= floor {
+			filtered = append(filtered, mark)
+		}
+	}
+	return filtered
+}
+
+// BlendMarks shades model marks toward market probabilities (keyed by market, then team),
+// returning weight*model + (1-weight)*market per mark; marks with no market probability
+// keep the model value
+func BlendMarks(modelMarks []OutrightMark, marketProbs map[string]map[string]float64, weight float64) []OutrightMark {
+	blended := make([]OutrightMark, len(modelMarks))
+	for i, mark := range modelMarks {
+		blended[i] = mark
+		if marketProb, exists := marketProbs[mark.Market][mark.Team]; exists {
+			blended[i].Mark = weight*mark.Mark + (1-weight)*marketProb
+		}
+	}
+	return blended
+}
+
 // calcAllFixtureOdds calculates match odds for all possible team matchups in the league
 func CalcAllFixtureOdds(teamNames []string, ratings map[string]float64, homeAdvantage float64) []FixtureOdds {
+	return CalcAllFixtureOddsWithModel(teamNames, ratings, AdditiveHomeAdvantage(homeAdvantage))
+}
+
+// CalcFixtureOddsWithModel calculates match odds and derived markets for a single
+// "Home vs Away" fixture under the given home advantage model
+func CalcFixtureOddsWithModel(fixture string, ratings map[string]float64, homeModel HomeAdvantageModel) FixtureOdds {
+	// Create score matrix for this matchup
+	matrix := homeModel.NewScoreMatrix(fixture, ratings)
+	
+	// Get match probabilities [home_win, draw, away_win]
+	probabilities := matrix.MatchOdds()
+	
+	// Get Asian handicaps
+	asianHandicaps := matrix.AsianHandicaps()
+	
+	// Get total goals over/under
+	totalGoals := matrix.TotalGoals()
+	
+	// Get exact total goals with a residual tail bucket
+	exactTotalGoals := matrix.ExactTotalGoals(ExactTotalGoalsMax)
+	
+	// Get odd/even total goals
+	oddEvenGoals := matrix.OddEvenGoals()
+	
+	// Get both teams to score
+	bothTeamsToScore := matrix.BothTeamsToScore()
+	
+	// Get winning margin bands
+	winningMargins := matrix.WinningMargins(WinningMarginsMax)
+	
+	// Get lambda values
+	lambdas := [2]float64{matrix.HomeLambda, matrix.AwayLambda}
+	
+	// Get the most likely scoreline
+	homeGoals, awayGoals, scoreProb := matrix.MostLikelyScore()
+	
+	return FixtureOdds{
+		Fixture:        fixture,
+		Probabilities:  [3]float64{probabilities[0], probabilities[1], probabilities[2]},
+		AsianHandicaps: asianHandicaps,
+		TotalGoals:     totalGoals,
+		ExactTotalGoals: exactTotalGoals,
+		OddEvenGoals:   oddEvenGoals,
+		BothTeamsToScore: bothTeamsToScore,
+		WinningMargins: winningMargins,
+		Lambdas:        lambdas,
+		MostLikelyScore: ScoreCell{HomeGoals: homeGoals, AwayGoals: awayGoals, Probability: scoreProb},
+	}
+}
+
+// CalcAllFixtureOddsWithModel calculates match odds for all possible team matchups under
+// the given home advantage model
+func CalcAllFixtureOddsWithModel(teamNames []string, ratings map[string]float64, homeModel HomeAdvantageModel) []FixtureOdds {
 	var fixtureOdds []FixtureOdds
 	
 	// Generate odds for all team combinations (n * (n-1) fixtures)
@@ -88,28 +183,7 @@ func CalcAllFixtureOdds(teamNames []string, ratings map[string]float64, homeAdva
 			if i != j { // Skip same team vs same team
 				fixture := fmt.Sprintf("%s vs %s", homeTeam, awayTeam)
 				
-				// Create score matrix for this matchup
-				matrix := NewScoreMatrix(fixture, ratings, homeAdvantage)
-				
-				// Get match probabilities [home_win, draw, away_win]
-				probabilities := matrix.MatchOdds()
-				
-				// Get Asian handicaps
-				asianHandicaps := matrix.AsianHandicaps()
-				
-				// Get total goals over/under
-				totalGoals := matrix.TotalGoals()
-				
-				// Get lambda values
-				lambdas := [2]float64{matrix.HomeLambda, matrix.AwayLambda}
-				
-				fixtureOdds = append(fixtureOdds, FixtureOdds{
-					Fixture:        fixture,
-					Probabilities:  [3]float64{probabilities[0], probabilities[1], probabilities[2]},
-					AsianHandicaps: asianHandicaps,
-					TotalGoals:     totalGoals,
-					Lambdas:        lambdas,
-				})
+				fixtureOdds = append(fixtureOdds, CalcFixtureOddsWithModel(fixture, ratings, homeModel))
 			}
 		}
 	}
@@ -122,6 +196,53 @@ func CalcAllFixtureOdds(teamNames []string, ratings map[string]float64, homeAdva
 	return fixtureOdds
 }
 
+// CalcSurvivalProbability calculates the probability of finishing above the bottom
+// relegationSpots positions from a team's position probabilities. The simulated
+// paths already account for games in hand, so a team currently outside the drop
+// zone is judged on where it finishes rather than where it stands today
+func CalcSurvivalProbability(positionProbs []float64, relegationSpots int) float64 {
+	if relegationSpots <= 0 || len(positionProbs) == 0 {
+		return 1.0
+	}
+	if relegationSpots >= len(positionProbs) {
+		return 0.0
+	}
+	
+	relegated := 0.0
+	for _, prob := range positionProbs[len(positionProbs)-relegationSpots:] {
+		relegated += prob
+	}
+	
+	// Clamp to guard against accumulated rounding in the position probabilities
+	return math.Max(0.0, math.Min(1.0, 1.0-relegated))
+}
+
+// ValidateQualificationBands checks that each band is a 1-based inclusive [first, last]
+// position range lying within a league of nTeams
+func ValidateQualificationBands(bands map[string][2]int, nTeams int) error {
+	for name, band := range bands {
+		if band[0] < 1 || band[1] < band[0] || band[1] > nTeams {
+			return fmt.Errorf("qualification band %s has invalid positions [%d, %d] for %d teams", 
+				name, band[0], band[1], nTeams)
+		}
+	}
+	return nil
+}
+
+// CalcQualificationProbabilities sums a team's position probabilities over each named
+// band, where bands are 1-based inclusive positions e.g. {"UCL": [1, 4], "UEL": [5, 6]}
+func CalcQualificationProbabilities(positionProbs []float64, bands map[string][2]int) map[string]float64 {
+	probabilities := make(map[string]float64)
+	for name, band := range bands {
+		total := 0.0
+		for pos := band[0]; pos <= band[1] && pos <= len(positionProbs); pos++ {
+			total += positionProbs[pos-1]
+		}
+		probabilities[name] = total
+	}
+	return probabilities
+}
+
 // sumProduct calculates the dot product of two float64 slices for market mark calculations
 func sumProduct(x, y []float64) float64 {
 	if len(x) != len(y) {
@@ -133,4 +254,47 @@ func sumProduct(x, y []float64) float64 {
 		sum += x[i] * y[i]
 	}
 	return sum
-}
\ No newline at end of file
+}
+
+// MakeBook builds a priced book for one market from its marks, adding a target margin
+// so the implied probabilities sum to (1+margin) times the fair total (1+margin for a
+// winner market). The margin is spread proportionally to each mark, equally across
+// outcomes ("equal_margin") or in proportion to squared marks so favourites carry more
+// of it ("favourite_weighted"). Teams with a zero mark have no price and are omitted
+func MakeBook(marks []OutrightMark, market string, margin float64, method string) (map[string]float64, error) {
+	var teams []string
+	var probs []float64
+	for _, mark := range marks {
+		if mark.Market == market && mark.Mark > 0 {
+			teams = append(teams, mark.Team)
+			probs = append(probs, mark.Mark)
+		}
+	}
+	if len(teams) == 0 {
+		return nil, fmt.Errorf("no priced marks for market %s", market)
+	}
+	
+	total := sumFloats(probs)
+	sumSquares := 0.0
+	for _, p := range probs {
+		sumSquares += p * p
+	}
+	
+	book := make(map[string]float64, len(teams))
+	for i, team := range teams {
+		var implied float64
+		switch method {
+		case "", MarginProportional:
+			implied = probs[i] * (1 + margin)
+		case BookEqualMargin:
+			implied = probs[i] + margin*total/float64(len(probs))
+		case BookFavouriteWeighted:
+			implied = probs[i] + margin*total*probs[i]*probs[i]/sumSquares
+		default:
+			return nil, fmt.Errorf("unknown book method %s", method)
+		}
+		book[team] = 1.0 / implied
+	}
+	
+	return book, nil
+}
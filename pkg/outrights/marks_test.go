@@ -0,0 +1,202 @@
+package outrights
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestBlendMarks(t *testing.T) {
+	modelMarks := []OutrightMark{
+		{Market: "Winner", Team: "A", Mark: 0.6},
+		{Market: "Winner", Team: "B", Mark: 0.4},
+		{Market: "Relegation", Team: "B", Mark: 0.2},
+	}
+	marketProbs := map[string]map[string]float64{
+		"Winner": {"A": 0.5, "B": 0.5},
+	}
+	tests := []struct {
+		name   string
+		weight float64
+		want   []float64
+	}{
+		{"weight 0 returns market values", 0, []float64{0.5, 0.5, 0.2}},
+		{"weight 1 returns model values", 1, []float64{0.6, 0.4, 0.2}},
+		{"halfway", 0.5, []float64{0.55, 0.45, 0.2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blended := BlendMarks(modelMarks, marketProbs, tt.weight)
+			for i, mark := range blended {
+				if math.Abs(mark.Mark-tt.want[i]) > 1e-12 {
+					t.Errorf("%s %s = %g, want %g", mark.Market, mark.Team, mark.Mark, tt.want[i])
+				}
+			}
+		})
+	}
+	if modelMarks[0].Mark != 0.6 {
+		t.Error("BlendMarks modified its input")
+	}
+}
+
+func TestCalcSurvivalProbability(t *testing.T) {
+	tests := []struct {
+		name            string
+		positionProbs   []float64
+		relegationSpots int
+		want            float64
+	}{
+		{"mid-table team safe", []float64{0, 0.1, 0.6, 0.3, 0, 0}, 2, 1},
+		{"mid-table team at slight risk", []float64{0, 0.1, 0.5, 0.38, 0.02, 0}, 2, 0.98},
+		{"bottom team", []float64{0, 0, 0, 0.1, 0.3, 0.6}, 2, 0.1},
+		{"no relegation", []float64{0, 0, 0, 0, 0, 1}, 0, 1},
+		{"everyone relegated", []float64{1, 0, 0}, 3, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CalcSurvivalProbability(tt.positionProbs, tt.relegationSpots); math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("CalcSurvivalProbability = %g, want %g", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMakeBook(t *testing.T) {
+	marks := []OutrightMark{
+		{Market: "Winner", Team: "A", Mark: 0.55},
+		{Market: "Winner", Team: "B", Mark: 0.3},
+		{Market: "Winner", Team: "C", Mark: 0.15},
+		{Market: "Winner", Team: "D", Mark: 0},
+		{Market: "Top 2", Team: "A", Mark: 0.9},
+	}
+	order := []string{"A", "B", "C"}
+	for _, method := range []string{"", MarginProportional, BookEqualMargin, BookFavouriteWeighted} {
+		t.Run(method, func(t *testing.T) {
+			book, err := MakeBook(marks, "Winner", 0.08, method)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(book) != 3 {
+				t.Fatalf("book %v, want prices for the three marked teams", book)
+			}
+			overround := 0.0
+			for _, price := range book {
+				overround += 1 / price
+			}
+			if math.Abs(overround-1.08) > 1e-12 {
+				t.Errorf("overround %g, want 1.08", overround)
+			}
+			for i := 1; i < len(order); i++ {
+				if book[order[i-1]] >= book[order[i]] {
+					t.Errorf("%s at %g not shorter than %s at %g", order[i-1], book[order[i-1]], order[i], book[order[i]])
+				}
+			}
+		})
+	}
+
+	// Favourite weighting loads the margin onto A, equal margin onto the longshots
+	proportional, _ := MakeBook(marks, "Winner", 0.08, MarginProportional)
+	equal, _ := MakeBook(marks, "Winner", 0.08, BookEqualMargin)
+	favourite, _ := MakeBook(marks, "Winner", 0.08, BookFavouriteWeighted)
+	if !(favourite["A"] < proportional["A"] && proportional["A"] < equal["A"]) {
+		t.Errorf("favourite prices %g, %g, %g not ordered favourite_weighted < proportional < equal_margin",
+			favourite["A"], proportional["A"], equal["A"])
+	}
+
+	if _, err := MakeBook(marks, "Winner", 0.08, "flat"); err == nil {
+		t.Error("expected an error for an unknown method")
+	}
+	if _, err := MakeBook(marks, "Relegation", 0.08, ""); err == nil {
+		t.Error("expected an error for a market with no marks")
+	}
+}
+
+func TestCalcQualificationProbabilities(t *testing.T) {
+	positionProbs := []float64{0.25, 0.25, 0.125, 0.125, 0.25, 0}
+	bands := map[string][2]int{"UCL": {1, 4}, "UEL": {5, 5}, "Title": {1, 1}, "Bottom": {6, 6}}
+	want := map[string]float64{"UCL": 0.75, "UEL": 0.25, "Title": 0.25, "Bottom": 0}
+	got := CalcQualificationProbabilities(positionProbs, bands)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CalcQualificationProbabilities = %v, want %v", got, want)
+	}
+	if got := CalcQualificationProbabilities(positionProbs, nil); len(got) != 0 {
+		t.Errorf("expected no probabilities without bands, got %v", got)
+	}
+}
+
+func TestValidateQualificationBands(t *testing.T) {
+	tests := []struct {
+		name    string
+		bands   map[string][2]int
+		wantErr bool
+	}{
+		{"no bands", nil, false},
+		{"valid bands", map[string][2]int{"UCL": {1, 4}, "UEL": {5, 6}}, false},
+		{"whole league", map[string][2]int{"All": {1, 6}}, false},
+		{"zero-based", map[string][2]int{"UCL": {0, 4}}, true},
+		{"reversed", map[string][2]int{"UEL": {6, 5}}, true},
+		{"past the bottom", map[string][2]int{"UCL": {5, 7}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateQualificationBands(tt.bands, 6)
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Errorf("ValidateQualificationBands error = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFilterMarks(t *testing.T) {
+	marks := []OutrightMark{
+		{Market: "Winner", Team: "A", Mark: 0.9},
+		{Market: "Winner", Team: "B", Mark: 0.0995},
+		{Market: "Winner", Team: "C", Mark: 0.0005},
+		{Market: "Winner", Team: "D", Mark: 0},
+	}
+	tests := []struct {
+		name  string
+		floor float64
+		want  []string
+	}{
+		{"zero floor keeps zeros", 0, []string{"A", "B", "C", "D"}},
+		{"long shots hidden", 0.001, []string{"A", "B"}},
+		{"floor is inclusive", 0.0995, []string{"A", "B"}},
+		{"everything hidden", 1, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered := FilterMarks(marks, tt.floor)
+			teams := make([]string, len(filtered))
+			for i, mark := range filtered {
+				teams[i] = mark.Team
+			}
+			if !reflect.DeepEqual(teams, tt.want) {
+				t.Errorf("FilterMarks kept %v, want %v", teams, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalcPositionProbabilitiesForMarkets(t *testing.T) {
+	sp := handPaths()
+	markets := []Market{
+		{Name: "AC", Teams: []string{"A", "C"}},
+		{Name: "CA", Teams: []string{"C", "A"}},
+		{Name: "Unscoped"},
+	}
+	subset := map[string][]float64{"A": {1, 0}, "C": {0, 1}}
+	want := map[string]map[string][]float64{
+		"default": {"A": {0.5, 0.5, 0}, "B": {0.5, 0.25, 0.25}, "C": {0, 0.25, 0.75}},
+		"AC":      subset,
+		"CA":      subset,
+	}
+	if got := CalcPositionProbabilitiesForMarkets(sp, markets, true); !reflect.DeepEqual(got, want) {
+		t.Errorf("with default = %v, want %v", got, want)
+	}
+
+	delete(want, "default")
+	if got := CalcPositionProbabilitiesForMarkets(sp, markets, false); !reflect.DeepEqual(got, want) {
+		t.Errorf("without default = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,235 @@
+package outrights
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// Price output formats
+const (
+	PriceDecimal    = "decimal"
+	PriceAmerican   = "american"
+	PriceFractional = "fractional"
+)
+
+// FractionalMaxDenominator bounds the denominator of fractional prices, so a decimal
+// price that isn't an exact fraction is shown as its nearest readable one
+const FractionalMaxDenominator = 100
+
+// Statistical helpers shared by the solver diagnostics and analysis scripts, plus
+// price format conversions
+
+// Mean returns the arithmetic mean of values, or 0 if empty
+func Mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	return sumFloats(values) / float64(len(values))
+}
+
+// StdDev returns the sample standard deviation of values (n-1 denominator),
+// or 0 with fewer than two values
+func StdDev(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	mean := Mean(values)
+	sumSquaredDiff := 0.0
+	for _, v := range values {
+		diff := v - mean
+		sumSquaredDiff += diff * diff
+	}
+	return math.Sqrt(sumSquaredDiff / float64(len(values)-1))
+}
+
+// MinMax returns the smallest and largest of values, or 0, 0 if empty
+func MinMax(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	min, max := values[0], values[0]
+	for _, v := range values[1:] {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+	return min, max
+}
+
+// RankCorrelation returns the Spearman rank correlation between two rankings, best first,
+// over the teams present in both: 1 for the same order, -1 for the reverse, 0 with fewer
+// than two teams in common
+func RankCorrelation(modelRanking, marketRanking []string) float64 {
+	marketRanks := make(map[string]int, len(marketRanking))
+	for i, name := range marketRanking {
+		marketRanks[name] = i
+	}
+	
+	// Re-rank the common teams in market order so teams missing from either leave no gaps
+	var common []string
+	for _, name := range modelRanking {
+		if _, exists := marketRanks[name]; exists {
+			common = append(common, name)
+		}
+	}
+	n := len(common)
+	if n < 2 {
+		return 0
+	}
+	byMarket := make([]string, n)
+	copy(byMarket, common)
+	sort.Slice(byMarket, func(i, j int) bool {
+		return marketRanks[byMarket[i]] < marketRanks[byMarket[j]]
+	})
+	commonMarketRanks := make(map[string]int, n)
+	for i, name := range byMarket {
+		commonMarketRanks[name] = i
+	}
+	
+	sumSquaredDiff := 0.0
+	for i, name := range common {
+		diff := float64(i - commonMarketRanks[name])
+		sumSquaredDiff += diff * diff
+	}
+	return 1 - 6*sumSquaredDiff/float64(n*(n*n-1))
+}
+
+// RecommendedNPaths returns the number of simulation paths needed for every mark's
+// standard error to be at most targetStdErr, from the binomial variance p(1-p)/n at the
+// worst case p=0.5; returns 0 for a non-positive target
+func RecommendedNPaths(targetStdErr float64) int {
+	if targetStdErr <= 0 {
+		return 0
+	}
+	return int(math.Ceil(0.25 / (targetStdErr * targetStdErr)))
+}
+
+// NewRand returns a random source for one run, seeded with seed for a reproducible run
+// or from the shared source when seed is 0. Runs never share a source, so concurrent
+// runs can't disturb each other's draws
+func NewRand(seed int64) *rand.Rand {
+	if seed == 0 {
+		seed = rand.Int63()
+	}
+	return rand.New(rand.NewSource(seed))
+}
+
+// workers counts the goroutines doing parallel work against the package-wide limit
+var workers = struct {
+	mu     sync.Mutex
+	freed  *sync.Cond
+	limit  int // 0 is unlimited
+	active int
+}{}
+
+func init() {
+	workers.freed = sync.NewCond(&workers.mu)
+}
+
+// SetMaxConcurrency caps the goroutines doing parallel work at once across the package,
+// shared by every concurrent run; n <= 0 removes the cap. Lowering it doesn't interrupt
+// running work but holds new work until the active count falls below the new cap.
+// Goroutines that only wait on others, such as restart coordinators, don't count against it
+func SetMaxConcurrency(n int) {
+	workers.mu.Lock()
+	defer workers.mu.Unlock()
+	if n < 0 {
+		n = 0
+	}
+	workers.limit = n
+	workers.freed.Broadcast()
+}
+
+// acquireWorker blocks until a parallel work slot is free and returns the function that
+// frees it, to be called once the work is done
+func acquireWorker() func() {
+	workers.mu.Lock()
+	for workers.limit > 0 && workers.active >= workers.limit {
+		workers.freed.Wait()
+	}
+	workers.active++
+	workers.mu.Unlock()
+	
+	return func() {
+		workers.mu.Lock()
+		workers.active--
+		workers.mu.Unlock()
+		workers.freed.Signal()
+	}
+}
+
+// DecimalToAmerican converts a decimal price (> 1) to American odds: +100*(d-1) for
+// odds-against prices, -100/(d-1) for odds-on prices
+func DecimalToAmerican(decimal float64) float64 {
+	if decimal >= 2 {
+		return 100 * (decimal - 1)
+	}
+	return -100 / (decimal - 1)
+}
+
+// DecimalToFractional converts a decimal price (> 1) to fractional odds in lowest terms,
+// e.g. 3.0 -> "2/1", using the closest fraction with denominator up to FractionalMaxDenominator
+func DecimalToFractional(decimal float64) string {
+	numerator, denominator := approximateFraction(decimal-1, FractionalMaxDenominator)
+	return fmt.Sprintf("%d/%d", numerator, denominator)
+}
+
+// approximateFraction finds the continued-fraction convergent of value closest to it
+// with denominator at most maxDenominator; convergents are already in lowest terms
+func approximateFraction(value float64, maxDenominator int64) (int64, int64) {
+	// Convergents h/k, starting from the seeds 1/0 and 0/1
+	h0, h1 := int64(0), int64(1)
+	k0, k1 := int64(1), int64(0)
+	remainder := value
+	for {
+		a := int64(math.Floor(remainder))
+		h, k := a*h1+h0, a*k1+k0
+		if k > maxDenominator {
+			break
+		}
+		h0, h1 = h1, h
+		k0, k1 = k1, k
+		fractional := remainder - float64(a)
+		if fractional < 1e-9 {
+			break
+		}
+		remainder = 1 / fractional
+	}
+	return h1, k1
+}
+
+// ValidatePriceFormat checks that format names a supported price output format
+func ValidatePriceFormat(format string) error {
+	switch format {
+	case "", PriceDecimal, PriceAmerican, PriceFractional:
+		return nil
+	}
+	return fmt.Errorf("unknown price format %s", format)
+}
+
+// FormatPrices converts probabilities to fair prices written in format; a zero or
+// certain probability has no meaningful price and is left blank
+func FormatPrices(probabilities []float64, format string) []string {
+	prices := make([]string, len(probabilities))
+	for i, probability := range probabilities {
+		if probability <= 0 || probability >= 1 {
+			continue
+		}
+		decimal := 1 / probability
+		switch format {
+		case PriceAmerican:
+			prices[i] = fmt.Sprintf("%+.0f", DecimalToAmerican(decimal))
+		case PriceFractional:
+			prices[i] = DecimalToFractional(decimal)
+		default:
+			prices[i] = fmt.Sprintf("%.2f", decimal)
+		}
+	}
+	return prices
+}
@@ -0,0 +1,158 @@
+package outrights
+
+import (
+	"math"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// concurrencyProbe is an objective recording the most evaluations ever running at once
+type concurrencyProbe struct {
+	active, peak int64
+}
+
+func (p *concurrencyProbe) objective(x []float64) float64 {
+	n := atomic.AddInt64(&p.active, 1)
+	for {
+		peak := atomic.LoadInt64(&p.peak)
+		if n <= peak || atomic.CompareAndSwapInt64(&p.peak, peak, n) {
+			break
+		}
+	}
+	time.Sleep(200 * time.Microsecond)
+	atomic.AddInt64(&p.active, -1)
+	return x[0] * x[0]
+}
+
+func TestSetMaxConcurrency(t *testing.T) {
+	t.Cleanup(func() { SetMaxConcurrency(0) })
+	x0 := []float64{1}
+	bounds := [][]float64{{-2, 2}}
+
+	for _, limit := range []int{1, 3} {
+		SetMaxConcurrency(limit)
+		probe := &concurrencyProbe{}
+		newGeneticAlgorithm(testGAOptions(5), rand.New(rand.NewSource(1))).optimize(probe.objective, x0, bounds)
+		if probe.peak > int64(limit) {
+			t.Errorf("limit %d: %d evaluations ran at once", limit, probe.peak)
+		}
+	}
+
+	// Changing the cap mid-run must never let more than the larger cap run at once
+	SetMaxConcurrency(2)
+	probe := &concurrencyProbe{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		newGeneticAlgorithm(testGAOptions(20), rand.New(rand.NewSource(1))).optimize(probe.objective, x0, bounds)
+	}()
+	for limit := 2; ; limit = 6 - limit {
+		select {
+		case <-done:
+			if probe.peak > 4 {
+				t.Errorf("toggling the limit between 2 and 4: %d evaluations ran at once", probe.peak)
+			}
+			return
+		case <-time.After(time.Millisecond):
+			SetMaxConcurrency(limit)
+		}
+	}
+}
+
+func TestRankCorrelation(t *testing.T) {
+	prices := map[string]float64{"A": 2.5, "B": 4, "C": 9, "D": 26}
+	tests := []struct {
+		name         string
+		modelRanking []string
+		want         float64
+	}{
+		{"same order", []string{"A", "B", "C", "D"}, 1},
+		{"reversed", []string{"D", "C", "B", "A"}, -1},
+		{"one swap", []string{"B", "A", "C", "D"}, 0.8},
+		{"teams missing from the market are ignored", []string{"A", "E", "B", "C", "D"}, 1},
+		{"fewer than two common teams", []string{"A", "E"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RankCorrelation(tt.modelRanking, MarketImpliedRanking(prices))
+			if math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("RankCorrelation = %g, want %g", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPriceConversions(t *testing.T) {
+	tests := []struct {
+		decimal        float64
+		wantAmerican   float64
+		wantFractional string
+	}{
+		{3.0, 200, "2/1"},
+		{2.0, 100, "1/1"},
+		{1.5, -200, "1/2"},
+		{2.375, 137.5, "11/8"},
+		{1 + 1/0.3, 1000.0 / 3, "10/3"},
+		{1 + math.Pi, 100 * math.Pi, "22/7"},
+	}
+	for _, tt := range tests {
+		if got := DecimalToAmerican(tt.decimal); math.Abs(got-tt.wantAmerican) > 1e-9 {
+			t.Errorf("DecimalToAmerican(%g) = %g, want %g", tt.decimal, got, tt.wantAmerican)
+		}
+		if got := DecimalToFractional(tt.decimal); got != tt.wantFractional {
+			t.Errorf("DecimalToFractional(%g) = %s, want %s", tt.decimal, got, tt.wantFractional)
+		}
+	}
+}
+
+func TestRecommendedNPaths(t *testing.T) {
+	tests := []struct {
+		targetStdErr float64
+		want         int
+	}{
+		{0.01, 2500},
+		{0.005, 10000},
+		{0.001, 250000},
+		{0.3, 3}, // Rounded up so the target is still met
+		{0, 0},
+		{-0.01, 0},
+	}
+	for _, tt := range tests {
+		got := RecommendedNPaths(tt.targetStdErr)
+		if got != tt.want {
+			t.Errorf("RecommendedNPaths(%g) = %d, want %d", tt.targetStdErr, got, tt.want)
+		}
+		if got > 0 && math.Sqrt(0.25/float64(got)) > tt.targetStdErr {
+			t.Errorf("%d paths miss the %g standard error target", got, tt.targetStdErr)
+		}
+	}
+}
+
+func TestStats(t *testing.T) {
+	tests := []struct {
+		name             string
+		values           []float64
+		mean, std        float64
+		wantMin, wantMax float64
+	}{
+		{"empty", nil, 0, 0, 0, 0},
+		{"single value", []float64{3.5}, 3.5, 0, 3.5, 3.5},
+		{"sample std", []float64{2, 4, 4, 4, 5, 5, 7, 9}, 5, math.Sqrt(32.0 / 7), 2, 9},
+		{"negatives", []float64{-1, 1}, 0, math.Sqrt2, -1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Mean(tt.values); math.Abs(got-tt.mean) > 1e-12 {
+				t.Errorf("Mean = %g, want %g", got, tt.mean)
+			}
+			if got := StdDev(tt.values); math.Abs(got-tt.std) > 1e-12 {
+				t.Errorf("StdDev = %g, want %g", got, tt.std)
+			}
+			if gotMin, gotMax := MinMax(tt.values); gotMin != tt.wantMin || gotMax != tt.wantMax {
+				t.Errorf("MinMax = %g, %g, want %g, %g", gotMin, gotMax, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
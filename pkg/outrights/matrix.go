@@ -1,6 +1,7 @@
 package outrights
 
 import (
+	"fmt"
 	"math"
 	"math/rand"
 )
@@ -8,8 +9,18 @@ import (
 const (
 	DefaultN   = 11
 	DefaultRho = 0.1
-	GDMultiplier = 1e-4
 	NoiseMultiplier = 1e-8
+	ExactTotalGoalsMax = 6
+	WinningMarginsMax = 3
+	LambdaMin = 1e-6
+	RhoMin = -1.0
+	RhoMax = 1.0
+)
+
+// Rho sign conventions, naming what a positive rho does to the draw probability
+const (
+	RhoConventionFewerDraws = "fewer_draws" // Dixon-Coles' own, used internally: positive rho shrinks 1-1 and grows 1-0 and 0-1
+	RhoConventionMoreDraws  = "more_draws"  // Positive rho raises the draw probability; negated before use
 )
 
 
@@ -21,22 +32,166 @@ type ScoreMatrix struct {
 	N           int
 }
 
-func NewScoreMatrix(eventName string, ratings map[string]float64, homeAdvantage float64) *ScoreMatrix {
+// ScoreCell is one scoreline's probability in a flattened score matrix
+type ScoreCell struct {
+	HomeGoals   int     `json:"home_goals"`
+	AwayGoals   int     `json:"away_goals"`
+	Probability float64 `json:"probability"`
+}
+
+// HomeAdvantageModel describes how playing at home shifts a fixture's lambdas
+// The default additive model adds HomeAdvantage goals to the home rating; the split
+// model instead scales the home rating by HomeMultiplier and the away rating by
+// AwayMultiplier, separating the home boost from the away penalty; the multiplicative
+// model scales only the home rating, by HomeMultiplier
+type HomeAdvantageModel struct {
+	HomeAdvantage  float64 `json:"home_advantage"`
+	Split          bool    `json:"split"`
+	Multiplicative bool    `json:"multiplicative,omitempty"`
+	HomeMultiplier float64 `json:"home_multiplier,omitempty"`
+	AwayMultiplier float64 `json:"away_multiplier,omitempty"`
+	Rho            *float64 `json:"rho,omitempty"` // Dixon-Coles rho in the fewer_draws convention; nil uses DefaultRho
+}
+
+// dixonColesRho returns the model's Dixon-Coles rho, DefaultRho unless set
+func (m HomeAdvantageModel) dixonColesRho() float64 {
+	if m.Rho == nil {
+		return DefaultRho
+	}
+	return *m.Rho
+}
+
+// AdditiveHomeAdvantage returns the default model adding homeAdvantage to the home lambda
+func AdditiveHomeAdvantage(homeAdvantage float64) HomeAdvantageModel {
+	return HomeAdvantageModel{HomeAdvantage: homeAdvantage}
+}
+
+// MultiplicativeHomeAdvantage returns the model scaling the home lambda by factor
+func MultiplicativeHomeAdvantage(factor float64) HomeAdvantageModel {
+	return HomeAdvantageModel{Multiplicative: true, HomeMultiplier: factor}
+}
+
+// Lambdas converts home and away ratings to [home, away] lambdas under the model
+func (m HomeAdvantageModel) Lambdas(homeRating, awayRating float64) (float64, float64) {
+	if m.Split {
+		return homeRating * m.HomeMultiplier, awayRating * m.AwayMultiplier
+	}
+	if m.Multiplicative {
+		return homeRating * m.HomeMultiplier, awayRating
+	}
+	return homeRating + m.HomeAdvantage, awayRating
+}
+
+// NewScoreMatrix builds the score matrix for eventName under the model
+func (m HomeAdvantageModel) NewScoreMatrix(eventName string, ratings map[string]float64) *ScoreMatrix {
 	homeTeam, awayTeam := ParseEventName(eventName)
-	homeLambda := ratings[homeTeam] + homeAdvantage
-	awayLambda := ratings[awayTeam]
+	return m.NewTeamsScoreMatrix(homeTeam, awayTeam, ratings)
+}
+
+// NewTeamsScoreMatrix builds the score matrix for homeTeam against awayTeam under the model
+func (m HomeAdvantageModel) NewTeamsScoreMatrix(homeTeam, awayTeam string, ratings map[string]float64) *ScoreMatrix {
+	homeLambda, awayLambda := m.Lambdas(ratings[homeTeam], ratings[awayTeam])
+	return NewScoreMatrixFromLambdas(homeLambda, awayLambda, m.dixonColesRho(), DefaultN)
+}
+
+func NewScoreMatrix(eventName string, ratings map[string]float64, homeAdvantage float64) *ScoreMatrix {
+	return AdditiveHomeAdvantage(homeAdvantage).NewScoreMatrix(eventName, ratings)
+}
+
+// MatchProbabilities returns [home_win, draw, away_win] for home against away under
+// additive home advantage, or all zeros if either team has no rating
+func MatchProbabilities(home, away string, ratings map[string]float64, homeAdvantage float64) [3]float64 {
+	_, homeExists := ratings[home]
+	_, awayExists := ratings[away]
+	if !homeExists || !awayExists {
+		return [3]float64{}
+	}
 	
+	odds := AdditiveHomeAdvantage(homeAdvantage).NewTeamsScoreMatrix(home, away, ratings).MatchOdds()
+	return [3]float64{odds[0], odds[1], odds[2]}
+}
+
+// NewScoreMatrixFromLambdas builds a score matrix directly from home and away lambdas,
+// for pricing hypothetical fixtures without a ratings map
+// Lambdas are clamped to LambdaMin so ratings at the lower bound can't produce
+// a degenerate or NaN matrix, and rho is clamped by ClampRho
+func NewScoreMatrixFromLambdas(homeLambda, awayLambda, rho float64, n int) *ScoreMatrix {
 	sm := &ScoreMatrix{
-		HomeLambda: homeLambda,
-		AwayLambda: awayLambda,
-		Rho:        DefaultRho,
-		N:          DefaultN,
+		HomeLambda: math.Max(LambdaMin, homeLambda),
+		AwayLambda: math.Max(LambdaMin, awayLambda),
+		Rho:        ClampRho(rho),
+		N:          n,
 	}
 	
 	sm.initMatrix()
 	return sm
 }
 
+// ClampRho limits rho to [RhoMin, RhoMax], inside which every Dixon-Coles adjusted cell
+// stays non-negative (1 - rho for 1-1 goes negative above 1); NaN falls back to
+// DefaultRho. It's a silent safety net for matrix building: options are checked once,
+// up front, by ValidateRho
+func ClampRho(rho float64) float64 {
+	if math.IsNaN(rho) {
+		return DefaultRho
+	}
+	return math.Max(RhoMin, math.Min(RhoMax, rho))
+}
+
+// ValidateRho checks that rho lies in [RhoMin, RhoMax], where every Dixon-Coles adjusted
+// cell stays non-negative
+func ValidateRho(rho float64) error {
+	if math.IsNaN(rho) || rho < RhoMin || rho > RhoMax {
+		return fmt.Errorf("rho %g outside [%g, %g], where score matrix cells go negative", rho, RhoMin, RhoMax)
+	}
+	return nil
+}
+
+// ApplyRhoConvention converts rho given in convention, "" meaning fewer_draws, to the
+// fewer_draws convention score matrices use
+func ApplyRhoConvention(rho float64, convention string) (float64, error) {
+	switch convention {
+	case "", RhoConventionFewerDraws:
+		return rho, nil
+	case RhoConventionMoreDraws:
+		return -rho, nil
+	}
+	return 0, fmt.Errorf("unknown rho convention %s", convention)
+}
+
+// CalibrateRho searches for the Dixon-Coles rho at which the model's average draw
+// probability across events matches targetDrawRate, e.g. a league's historical draw rate
+// Draw probability falls as rho rises, so bisect over [RhoMin, RhoMax]; targets outside
+// the achievable range return the nearest bound
+func CalibrateRho(events []Event, ratings map[string]float64, homeAdvantage float64, targetDrawRate float64) float64 {
+	if len(events) == 0 {
+		return DefaultRho
+	}
+	
+	homeModel := AdditiveHomeAdvantage(homeAdvantage)
+	drawRate := func(rho float64) float64 {
+		total := 0.0
+		for _, event := range events {
+			homeTeam, awayTeam := event.Teams()
+			homeLambda, awayLambda := homeModel.Lambdas(ratings[homeTeam], ratings[awayTeam])
+			total += NewScoreMatrixFromLambdas(homeLambda, awayLambda, rho, DefaultN).MatchOdds()[1]
+		}
+		return total / float64(len(events))
+	}
+	
+	lo, hi := RhoMin, RhoMax
+	for iter := 0; iter < 50; iter++ {
+		mid := (lo + hi) / 2
+		if drawRate(mid) > targetDrawRate {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	
+	return (lo + hi) / 2
+}
+
 func (sm *ScoreMatrix) initMatrix() {
 	sm.Matrix = make([][]float64, sm.N)
 	for i := range sm.Matrix {
@@ -75,6 +230,87 @@ func (sm *ScoreMatrix) MatchOdds() []float64 {
 	return []float64{homeWin / total, draw / total, awayWin / total}
 }
 
+// NoDrawMatchOdds returns match odds for competitions that resolve every game, splitting
+// the draw mass between the teams with homeShare going to the home side
+func (sm *ScoreMatrix) NoDrawMatchOdds(homeShare float64) []float64 {
+	return RedistributeDraw(sm.MatchOdds(), homeShare)
+}
+
+// InflatedMatchOdds returns match odds with the draw mass scaled by factor, for leagues
+// that draw more (or less) often than the Poisson model predicts
+func (sm *ScoreMatrix) InflatedMatchOdds(factor float64) []float64 {
+	return InflateDraw(sm.MatchOdds(), factor)
+}
+
+// InflateDraw scales the draw probability in [home_win, draw, away_win] by factor (capped
+// at 1) and rescales the win outcomes proportionally so the three still sum to 1
+func InflateDraw(odds []float64, factor float64) []float64 {
+	draw := math.Min(odds[1]*factor, 1.0)
+	winScale := 0.0
+	if odds[1] < 1.0 {
+		winScale = (1 - draw) / (1 - odds[1])
+	}
+	return []float64{
+		odds[0] * winScale,
+		draw,
+		odds[2] * winScale,
+	}
+}
+
+// RedistributeDraw moves the draw probability in [home_win, draw, away_win] onto the two
+// win outcomes, homeShare to the home side and the remainder to the away side
+func RedistributeDraw(odds []float64, homeShare float64) []float64 {
+	return []float64{
+		odds[0] + odds[1]*homeShare,
+		0.0,
+		odds[2] + odds[1]*(1-homeShare),
+	}
+}
+
+// Shootout outcomes per path, as returned by resolveDraws
+const (
+	ShootoutNone = 0  // Decided in normal time
+	ShootoutHome = 1  // Level, home side won the shootout
+	ShootoutAway = -1 // Level, away side won the shootout
+)
+
+// resolveDraws settles level scorelines with a shootout coin-flip, won by the home side
+// with probability homeShare, and returns each path's shootout outcome. Scores are left
+// as played
+func resolveDraws(scores [][]int, homeShare float64, rng *rand.Rand) []int {
+	shootouts := make([]int, len(scores))
+	for i, score := range scores {
+		if score[0] == score[1] {
+			if rng.Float64() < homeShare {
+				shootouts[i] = ShootoutHome
+			} else {
+				shootouts[i] = ShootoutAway
+			}
+		}
+	}
+	return shootouts
+}
+
+// decidedScore returns path i's score with any shootout winner credited a goal, so points
+// and wins go as for a one-goal win; shootouts may be nil if no draws were resolved
+func decidedScore(score []int, shootouts []int, i int) (int, int) {
+	homeGoals, awayGoals := score[0], score[1]
+	if shootouts != nil {
+		switch shootouts[i] {
+		case ShootoutHome:
+			homeGoals++
+		case ShootoutAway:
+			awayGoals++
+		}
+	}
+	return homeGoals, awayGoals
+}
+
+// ExpectedPoints returns the [home, away] expected points under 3/1/0 scoring
+func (sm *ScoreMatrix) ExpectedPoints() [2]float64 {
+	return [2]float64{sm.expectedHomePoints(), sm.expectedAwayPoints()}
+}
+
 func (sm *ScoreMatrix) expectedHomePoints() float64 {
 	odds := sm.MatchOdds()
 	return 3*odds[0] + odds[1]
@@ -85,7 +321,52 @@ func (sm *ScoreMatrix) expectedAwayPoints() float64 {
 	return 3*odds[2] + odds[1]
 }
 
-func (sm *ScoreMatrix) simulateScores(nPaths int) [][]int {
+// Grid returns the normalized score matrix as N*N labelled cells, home goals major, for
+// export to visualizations
+func (sm *ScoreMatrix) Grid() []ScoreCell {
+	total := sm.probability(func(i, j int) bool { return true })
+	
+	cells := make([]ScoreCell, 0, sm.N*sm.N)
+	for i := 0; i < sm.N; i++ {
+		for j := 0; j < sm.N; j++ {
+			cells = append(cells, ScoreCell{
+				HomeGoals:   i,
+				AwayGoals:   j,
+				Probability: sm.Matrix[i][j] / total,
+			})
+		}
+	}
+	return cells
+}
+
+// MostLikelyScore returns the single most probable scoreline and its normalized
+// probability; ties go to the lowest home then away goals
+func (sm *ScoreMatrix) MostLikelyScore() (home, away int, prob float64) {
+	for i := 0; i < sm.N; i++ {
+		for j := 0; j < sm.N; j++ {
+			if sm.Matrix[i][j] > sm.Matrix[home][away] {
+				home, away = i, j
+			}
+		}
+	}
+	
+	total := sm.probability(func(i, j int) bool { return true })
+	return home, away, sm.Matrix[home][away] / total
+}
+
+// modalScores returns the matrix's single most likely scoreline on every path, for
+// deterministic debugging simulations
+func (sm *ScoreMatrix) modalScores(nPaths int) [][]int {
+	bestHome, bestAway, _ := sm.MostLikelyScore()
+	
+	scores := make([][]int, nPaths)
+	for i := range scores {
+		scores[i] = []int{bestHome, bestAway}
+	}
+	return scores
+}
+
+func (sm *ScoreMatrix) simulateScores(nPaths int, rng *rand.Rand) [][]int {
 	// Flatten matrix and create cumulative distribution
 	var flatMatrix []float64
 	var indices [][]int
@@ -113,21 +394,61 @@ func (sm *ScoreMatrix) simulateScores(nPaths int) [][]int {
 		cumulative[i] = cumulative[i-1] + flatMatrix[i]
 	}
 	
+	// Pin the final bucket so rounding can't leave a draw above the cumulative total
+	last := len(cumulative) - 1
+	cumulative[last] = 1.0
+	
 	// Sample
 	results := make([][]int, nPaths)
 	for path := 0; path < nPaths; path++ {
-		r := rand.Float64()
+		r := rng.Float64()
 		for i, cum := range cumulative {
 			if r <= cum {
 				results[path] = []int{indices[i][0], indices[i][1]}
 				break
 			}
 		}
+		if results[path] == nil {
+			results[path] = []int{indices[last][0], indices[last][1]}
+		}
 	}
 	
 	return results
 }
 
+// sampleScore draws one scoreline from the distribution a score matrix with these
+// parameters would hold, without building the matrix: independent Poisson draws are
+// accepted in proportion to their Dixon-Coles adjustment, and scores past the matrix's
+// n-1 goals are redrawn, matching its truncation. Cheap enough to call per path; rho
+// must already lie in [RhoMin, RhoMax]
+func sampleScore(homeLambda, awayLambda, rho float64, n int, rng *rand.Rand) (int, int) {
+	homeLambda = math.Max(LambdaMin, homeLambda)
+	awayLambda = math.Max(LambdaMin, awayLambda)
+	maxAdjustment := math.Max(1, math.Max(1+rho/2, 1-rho))
+	
+	for {
+		home, away := poissonSample(homeLambda, rng), poissonSample(awayLambda, rng)
+		if home >= n || away >= n {
+			continue
+		}
+		if rng.Float64()*maxAdjustment <= dixonColesAdjustment(home, away, rho) {
+			return home, away
+		}
+	}
+}
+
+// poissonSample draws a Poisson variate by multiplying uniforms (Knuth), fine for the
+// small lambdas of football scores
+func poissonSample(lambda float64, rng *rand.Rand) int {
+	limit := math.Exp(-lambda)
+	k, product := 0, rng.Float64()
+	for product > limit {
+		k++
+		product *= rng.Float64()
+	}
+	return k
+}
+
 // asianHandicaps calculates Asian handicap probabilities at half-point intervals
 func (sm *ScoreMatrix) AsianHandicaps() [][2]interface{} {
 	var handicaps [][2]interface{}
@@ -181,6 +502,85 @@ func (sm *ScoreMatrix) TotalGoals() [][2]interface{} {
 	return totals
 }
 
+// ExactTotalGoals calculates P(total = k) for k = 0..maxK-1, with a final residual
+// bucket holding P(total >= maxK)
+func (sm *ScoreMatrix) ExactTotalGoals(maxK int) []float64 {
+	probs := make([]float64, maxK+1)
+	total := 0.0
+	
+	for i := 0; i < sm.N; i++ {
+		for j := 0; j < sm.N; j++ {
+			k := i + j
+			if k > maxK {
+				k = maxK
+			}
+			probs[k] += sm.Matrix[i][j]
+			total += sm.Matrix[i][j]
+		}
+	}
+	
+	for k := range probs {
+		probs[k] /= total
+	}
+	
+	return probs
+}
+
+// OddEvenGoals calculates the probability the total goals is [odd, even]
+func (sm *ScoreMatrix) OddEvenGoals() [2]float64 {
+	odd := sm.probability(func(i, j int) bool { return (i+j)%2 == 1 })
+	even := sm.probability(func(i, j int) bool { return (i+j)%2 == 0 })
+	
+	total := odd + even
+	return [2]float64{odd / total, even / total}
+}
+
+// BothTeamsToScore calculates the probability both teams score as [yes, no]
+func (sm *ScoreMatrix) BothTeamsToScore() [2]float64 {
+	yes := sm.probability(func(i, j int) bool { return i > 0 && j > 0 })
+	no := sm.probability(func(i, j int) bool { return i == 0 || j == 0 })
+	
+	total := yes + no
+	return [2]float64{yes / total, no / total}
+}
+
+// WinningMargins calculates winning margin bands keyed "home_1", "home_2", ...,
+// "home_<maxMargin>+", "draw", and likewise for "away_", with the "+" bands
+// holding the residual tail on each side
+func (sm *ScoreMatrix) WinningMargins(maxMargin int) map[string]float64 {
+	margins := make(map[string]float64)
+	total := 0.0
+	
+	bandKey := func(side string, margin int) string {
+		if margin >= maxMargin {
+			return fmt.Sprintf("%s_%d+", side, maxMargin)
+		}
+		return fmt.Sprintf("%s_%d", side, margin)
+	}
+	
+	for i := 0; i < sm.N; i++ {
+		for j := 0; j < sm.N; j++ {
+			var key string
+			switch {
+			case i > j:
+				key = bandKey("home", i-j)
+			case i < j:
+				key = bandKey("away", j-i)
+			default:
+				key = "draw"
+			}
+			margins[key] += sm.Matrix[i][j]
+			total += sm.Matrix[i][j]
+		}
+	}
+	
+	for key := range margins {
+		margins[key] /= total
+	}
+	
+	return margins
+}
+
 // factorial calculates the factorial of n
 func factorial(n int) float64 {
 	if n <= 1 {
@@ -0,0 +1,416 @@
+package outrights
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestValidateRho(t *testing.T) {
+	tests := []struct {
+		rho     float64
+		wantErr bool
+	}{
+		{0, false},
+		{DefaultRho, false},
+		{RhoMin, false},
+		{RhoMax, false},
+		{2, true},
+		{-1.5, true},
+		{math.NaN(), true},
+	}
+	for _, tt := range tests {
+		if err := ValidateRho(tt.rho); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateRho(%g) error %v, want error %v", tt.rho, err, tt.wantErr)
+		}
+	}
+}
+
+func TestClampedRhoKeepsCellsNonNegative(t *testing.T) {
+	for _, rho := range []float64{2, -3, math.NaN()} {
+		sm := NewScoreMatrixFromLambdas(1.2, 1.0, rho, DefaultN)
+		if sm.Rho < RhoMin || sm.Rho > RhoMax {
+			t.Errorf("rho %g: matrix rho %g outside [%g, %g]", rho, sm.Rho, RhoMin, RhoMax)
+		}
+		for i, row := range sm.Matrix {
+			for j, cell := range row {
+				if cell < 0 || math.IsNaN(cell) {
+					t.Errorf("rho %g: cell %d-%d is %g", rho, i, j, cell)
+				}
+			}
+		}
+	}
+}
+
+func TestRhoConvention(t *testing.T) {
+	ratings := map[string]float64{"A": 1.4, "B": 1.2}
+	drawProbability := func(rho float64) float64 {
+		homeModel := AdditiveHomeAdvantage(0.2)
+		homeModel.Rho = &rho
+		return homeModel.NewTeamsScoreMatrix("A", "B", ratings).MatchOdds()[1]
+	}
+
+	fewer, err := ApplyRhoConvention(0.2, RhoConventionFewerDraws)
+	if err != nil {
+		t.Fatal(err)
+	}
+	more, err := ApplyRhoConvention(0.2, RhoConventionMoreDraws)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if more != -fewer {
+		t.Errorf("more_draws rho %g, want %g", more, -fewer)
+	}
+
+	neutral := drawProbability(0)
+	if drawProbability(fewer) >= neutral {
+		t.Errorf("fewer_draws: draw probability %g not below %g at rho 0", drawProbability(fewer), neutral)
+	}
+	if drawProbability(more) <= neutral {
+		t.Errorf("more_draws: draw probability %g not above %g at rho 0", drawProbability(more), neutral)
+	}
+
+	if _, err := ApplyRhoConvention(0.2, "sideways"); err == nil {
+		t.Error("expected an error for an unknown convention")
+	}
+
+	// An unset rho prices as DefaultRho
+	if got, want := AdditiveHomeAdvantage(0.2).NewTeamsScoreMatrix("A", "B", ratings).MatchOdds()[1], drawProbability(DefaultRho); got != want {
+		t.Errorf("nil rho draw probability %g, want %g at DefaultRho", got, want)
+	}
+}
+
+// fixedSource is a rand.Source returning the same value on every draw
+type fixedSource int64
+
+func (s fixedSource) Int63() int64 { return int64(s) }
+func (s fixedSource) Seed(int64)   {}
+
+// fixedRand returns a *rand.Rand whose Float64 draws are all close to f
+func fixedRand(f float64) *rand.Rand {
+	return rand.New(fixedSource(int64(f * (1 << 63))))
+}
+
+func TestSimulateScoresDrawNearOne(t *testing.T) {
+	for _, lambdas := range [][2]float64{{1.4, 1.1}, {0.2, 0.2}, {6, 6}} {
+		sm := NewScoreMatrixFromLambdas(lambdas[0], lambdas[1], DefaultRho, DefaultN)
+		for _, draw := range []float64{0.9999999, math.Nextafter(1, 0)} {
+			scores := sm.simulateScores(10, fixedRand(draw))
+			for path, score := range scores {
+				if len(score) != 2 || score[0] < 0 || score[0] >= sm.N || score[1] < 0 || score[1] >= sm.N {
+					t.Errorf("lambdas %v draw %v: path %d got invalid score %v", lambdas, draw, path, score)
+				}
+			}
+		}
+	}
+}
+
+func TestScoreMatrixAtRatingBounds(t *testing.T) {
+	split := HomeAdvantageModel{Split: true, HomeMultiplier: 1.2, AwayMultiplier: 0.9}
+	tests := []struct {
+		name   string
+		matrix *ScoreMatrix
+	}{
+		{"zero lambdas", NewScoreMatrixFromLambdas(0, 0, DefaultRho, DefaultN)},
+		{"negative home lambda", NewScoreMatrixFromLambdas(-5, 6, DefaultRho, DefaultN)},
+		{"both lambdas at the cap", NewScoreMatrixFromLambdas(6, 6, DefaultRho, DefaultN)},
+		{"min vs max ratings", AdditiveHomeAdvantage(0.3).NewTeamsScoreMatrix("A", "B", map[string]float64{"A": RatingMin, "B": RatingMax})},
+		{"max vs min ratings", AdditiveHomeAdvantage(0.3).NewTeamsScoreMatrix("A", "B", map[string]float64{"A": RatingMax, "B": RatingMin})},
+		{"negative home advantage at the floor", AdditiveHomeAdvantage(-0.5).NewTeamsScoreMatrix("A", "B", map[string]float64{"A": RatingMin, "B": RatingMin})},
+		{"split model at the floor", split.NewTeamsScoreMatrix("A", "B", map[string]float64{"A": RatingMin, "B": RatingMin})},
+		{"multiplicative model at the cap", MultiplicativeHomeAdvantage(1.5).NewTeamsScoreMatrix("A", "B", map[string]float64{"A": RatingMax, "B": RatingMax})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, row := range tt.matrix.Matrix {
+				for j, cell := range row {
+					if math.IsNaN(cell) || cell < 0 {
+						t.Fatalf("cell %d-%d is %g", i, j, cell)
+					}
+				}
+			}
+			odds := tt.matrix.MatchOdds()
+			sum := 0.0
+			for _, prob := range odds {
+				if math.IsNaN(prob) {
+					t.Fatalf("match odds %v contain NaN", odds)
+				}
+				sum += prob
+			}
+			if math.Abs(sum-1) > 1e-9 {
+				t.Errorf("match odds %v sum to %g", odds, sum)
+			}
+		})
+	}
+}
+
+func TestMostLikelyScore(t *testing.T) {
+	tests := []struct {
+		name                   string
+		homeLambda, awayLambda float64
+		wantHome, wantAway     int
+	}{
+		{"strong home favourite", 2.5, 0.3, 2, 0},
+		{"strong away favourite", 0.3, 2.5, 0, 2},
+		{"low-scoring match", 0.4, 0.4, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm := NewScoreMatrixFromLambdas(tt.homeLambda, tt.awayLambda, DefaultRho, DefaultN)
+			home, away, prob := sm.MostLikelyScore()
+			if home != tt.wantHome || away != tt.wantAway {
+				t.Errorf("most likely score %d-%d, want %d-%d", home, away, tt.wantHome, tt.wantAway)
+			}
+			for _, cell := range sm.Grid() {
+				if cell.Probability > prob+1e-12 {
+					t.Errorf("%d-%d has probability %g above the modal %g", cell.HomeGoals, cell.AwayGoals, cell.Probability, prob)
+				}
+			}
+		})
+	}
+}
+
+func TestCalibrateRho(t *testing.T) {
+	ratings := map[string]float64{"A": 1.2, "B": 1.4, "C": 1.6}
+	events := []Event{{Name: "A vs B"}, {Name: "B vs C"}, {Name: "C vs A"}}
+	drawRate := func(rho float64) float64 {
+		total := 0.0
+		for _, event := range events {
+			home, away := event.Teams()
+			homeLambda, awayLambda := AdditiveHomeAdvantage(0.3).Lambdas(ratings[home], ratings[away])
+			total += NewScoreMatrixFromLambdas(homeLambda, awayLambda, rho, DefaultN).MatchOdds()[1]
+		}
+		return total / float64(len(events))
+	}
+
+	target := drawRate(-0.2)
+	rho := CalibrateRho(events, ratings, 0.3, target)
+	if math.Abs(drawRate(rho)-target) > 1e-9 {
+		t.Errorf("rho %g gives draw rate %g, want %g", rho, drawRate(rho), target)
+	}
+
+	// Unreachable targets land on the nearest bound
+	if rho := CalibrateRho(events, ratings, 0.3, 0.99); math.Abs(rho-RhoMin) > 1e-9 {
+		t.Errorf("high target: rho %g, want RhoMin", rho)
+	}
+	if rho := CalibrateRho(events, ratings, 0.3, 0.01); math.Abs(rho-RhoMax) > 1e-9 {
+		t.Errorf("low target: rho %g, want RhoMax", rho)
+	}
+	if rho := CalibrateRho(nil, ratings, 0.3, 0.25); rho != DefaultRho {
+		t.Errorf("no events: rho %g, want DefaultRho", rho)
+	}
+}
+
+func TestExactTotalGoals(t *testing.T) {
+	sm := NewScoreMatrixFromLambdas(2.5, 2.0, DefaultRho, DefaultN)
+	total := sm.probability(func(i, j int) bool { return true })
+	probs := sm.ExactTotalGoals(ExactTotalGoalsMax)
+	if len(probs) != ExactTotalGoalsMax+1 {
+		t.Fatalf("expected %d buckets, got %d", ExactTotalGoalsMax+1, len(probs))
+	}
+
+	sum := 0.0
+	for k, prob := range probs {
+		sum += prob
+		want := sm.probability(func(i, j int) bool { return i+j == k }) / total
+		if k == ExactTotalGoalsMax {
+			want = sm.probability(func(i, j int) bool { return i+j >= k }) / total
+		}
+		if math.Abs(prob-want) > 1e-12 {
+			t.Errorf("P(total bucket %d) = %g, want %g", k, prob, want)
+		}
+	}
+	if math.Abs(sum-1) > 1e-12 {
+		t.Errorf("total goals distribution sums to %g", sum)
+	}
+	// With 4.5 goals expected, six or more is far from negligible
+	if probs[ExactTotalGoalsMax] < 0.1 {
+		t.Errorf("residual bucket %g looks truncated", probs[ExactTotalGoalsMax])
+	}
+}
+
+func TestOddEvenGoals(t *testing.T) {
+	for _, lambdas := range [][2]float64{{1.5, 1.1}, {0.3, 0.2}, {3, 2.5}} {
+		// Without the Dixon-Coles adjustment the total is Poisson(home + away), whose
+		// even probability is (1 + exp(-2 lambda)) / 2
+		sm := NewScoreMatrixFromLambdas(lambdas[0], lambdas[1], 0, 30)
+		probs := sm.OddEvenGoals()
+		wantEven := (1 + math.Exp(-2*(lambdas[0]+lambdas[1]))) / 2
+		if math.Abs(probs[1]-wantEven) > 1e-9 || math.Abs(probs[0]-(1-wantEven)) > 1e-9 {
+			t.Errorf("lambdas %v: odd/even %v, want [%g %g]", lambdas, probs, 1-wantEven, wantEven)
+		}
+
+		probs = NewScoreMatrixFromLambdas(lambdas[0], lambdas[1], DefaultRho, DefaultN).OddEvenGoals()
+		if math.Abs(probs[0]+probs[1]-1) > 1e-12 {
+			t.Errorf("lambdas %v: odd/even %v don't sum to 1", lambdas, probs)
+		}
+	}
+}
+
+func TestWinningMargins(t *testing.T) {
+	sm := NewScoreMatrixFromLambdas(2.2, 0.9, DefaultRho, DefaultN)
+	margins := sm.WinningMargins(WinningMarginsMax)
+	keys := []string{"home_1", "home_2", "home_3+", "draw", "away_1", "away_2", "away_3+"}
+	if len(margins) != len(keys) {
+		t.Errorf("expected bands %v, got %v", keys, margins)
+	}
+
+	sum := 0.0
+	for _, key := range keys {
+		if _, exists := margins[key]; !exists {
+			t.Errorf("missing band %s", key)
+		}
+		sum += margins[key]
+	}
+	if math.Abs(sum-1) > 1e-12 {
+		t.Errorf("winning margins sum to %g", sum)
+	}
+
+	// Bands on each side add up to that side's match odds, with the tail in the "+" band
+	odds := sm.MatchOdds()
+	if home := margins["home_1"] + margins["home_2"] + margins["home_3+"]; math.Abs(home-odds[0]) > 1e-12 {
+		t.Errorf("home bands sum to %g, want home win %g", home, odds[0])
+	}
+	if math.Abs(margins["draw"]-odds[1]) > 1e-12 {
+		t.Errorf("draw band %g, want %g", margins["draw"], odds[1])
+	}
+	total := sm.probability(func(i, j int) bool { return true })
+	if tail := sm.probability(func(i, j int) bool { return i-j >= 3 }) / total; math.Abs(margins["home_3+"]-tail) > 1e-12 {
+		t.Errorf("home_3+ = %g, want %g", margins["home_3+"], tail)
+	}
+}
+
+func TestInflateDraw(t *testing.T) {
+	odds := []float64{0.5, 0.2, 0.3}
+	tests := []struct {
+		name   string
+		odds   []float64
+		factor float64
+		want   []float64
+	}{
+		{"unchanged", odds, 1, odds},
+		{"inflated", odds, 1.5, []float64{0.4375, 0.3, 0.2625}},
+		{"deflated to no draws", odds, 0, []float64{0.625, 0, 0.375}},
+		{"capped at certain draw", odds, 10, []float64{0, 1, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := InflateDraw(tt.odds, tt.factor)
+			for i := range got {
+				if math.Abs(got[i]-tt.want[i]) > 1e-12 {
+					t.Errorf("InflateDraw = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+	if odds[1] != 0.2 {
+		t.Error("InflateDraw modified its input")
+	}
+}
+
+func TestMatchProbabilities(t *testing.T) {
+	ratings := map[string]float64{"A": 1.6, "B": 1.1}
+	probs := MatchProbabilities("A", "B", ratings, 0.3)
+	odds := AdditiveHomeAdvantage(0.3).NewTeamsScoreMatrix("A", "B", ratings).MatchOdds()
+	for i := range probs {
+		if probs[i] != odds[i] {
+			t.Errorf("MatchProbabilities = %v, want the matrix odds %v", probs, odds)
+			break
+		}
+	}
+	if sum := probs[0] + probs[1] + probs[2]; math.Abs(sum-1) > 1e-12 {
+		t.Errorf("probabilities %v sum to %g", probs, sum)
+	}
+	if probs[0] <= probs[2] {
+		t.Errorf("stronger home side A priced %v", probs)
+	}
+
+	for _, pair := range [][2]string{{"A", "X"}, {"X", "B"}} {
+		if got := MatchProbabilities(pair[0], pair[1], ratings, 0.3); got != [3]float64{} {
+			t.Errorf("%s vs %s with an unrated team: %v, want zeros", pair[0], pair[1], got)
+		}
+	}
+}
+
+func TestBothTeamsToScore(t *testing.T) {
+	for _, lambdas := range [][2]float64{{1.5, 1.1}, {0.3, 2.4}} {
+		// Without the Dixon-Coles adjustment the sides score independently
+		probs := NewScoreMatrixFromLambdas(lambdas[0], lambdas[1], 0, 30).BothTeamsToScore()
+		wantYes := (1 - math.Exp(-lambdas[0])) * (1 - math.Exp(-lambdas[1]))
+		if math.Abs(probs[0]-wantYes) > 1e-9 || math.Abs(probs[1]-(1-wantYes)) > 1e-9 {
+			t.Errorf("lambdas %v: both teams to score %v, want [%g %g]", lambdas, probs, wantYes, 1-wantYes)
+		}
+	}
+
+	// Positive rho shrinks 1-1 and grows 1-0 and 0-1, so fewer games see both teams score
+	independent := NewScoreMatrixFromLambdas(1.4, 1.2, 0, DefaultN).BothTeamsToScore()
+	adjusted := NewScoreMatrixFromLambdas(1.4, 1.2, DefaultRho, DefaultN).BothTeamsToScore()
+	if adjusted[0] >= independent[0] {
+		t.Errorf("rho %g: both teams to score %g not below %g", DefaultRho, adjusted[0], independent[0])
+	}
+}
+
+func TestGrid(t *testing.T) {
+	sm := NewScoreMatrixFromLambdas(1.4, 1.1, DefaultRho, 5)
+	grid := sm.Grid()
+	if len(grid) != 25 {
+		t.Fatalf("expected 25 cells, got %d", len(grid))
+	}
+
+	total := sm.probability(func(i, j int) bool { return true })
+	sum := 0.0
+	for k, cell := range grid {
+		// Home goals major, away goals minor
+		if cell.HomeGoals != k/5 || cell.AwayGoals != k%5 {
+			t.Errorf("cell %d is %d-%d, want %d-%d", k, cell.HomeGoals, cell.AwayGoals, k/5, k%5)
+		}
+		if want := sm.Matrix[cell.HomeGoals][cell.AwayGoals] / total; math.Abs(cell.Probability-want) > 1e-15 {
+			t.Errorf("%d-%d probability %g, want %g", cell.HomeGoals, cell.AwayGoals, cell.Probability, want)
+		}
+		sum += cell.Probability
+	}
+	if math.Abs(sum-1) > 1e-12 {
+		t.Errorf("grid probabilities sum to %g", sum)
+	}
+}
+
+func TestExpectedPoints(t *testing.T) {
+	for _, lambdas := range [][2]float64{{1.6, 1.1}, {0.8, 2.2}, {1.3, 1.3}} {
+		sm := NewScoreMatrixFromLambdas(lambdas[0], lambdas[1], DefaultRho, DefaultN)
+		odds := sm.MatchOdds()
+		points := sm.ExpectedPoints()
+		if want := 3*odds[0] + odds[1]; math.Abs(points[0]-want) > 1e-12 {
+			t.Errorf("lambdas %v: home expected points %g, want %g", lambdas, points[0], want)
+		}
+		if want := 3*odds[2] + odds[1]; math.Abs(points[1]-want) > 1e-12 {
+			t.Errorf("lambdas %v: away expected points %g, want %g", lambdas, points[1], want)
+		}
+		// Every decisive game hands out three points and every draw two
+		if want := 3 - odds[1]; math.Abs(points[0]+points[1]-want) > 1e-12 {
+			t.Errorf("lambdas %v: points total %g, want %g", lambdas, points[0]+points[1], want)
+		}
+	}
+
+	sm := NewScoreMatrixFromLambdas(1.3, 1.3, DefaultRho, DefaultN)
+	if points := sm.ExpectedPoints(); math.Abs(points[0]-points[1]) > 1e-12 {
+		t.Errorf("evenly matched sides expect %v", points)
+	}
+}
+
+func TestNewScoreMatrixFromLambdas(t *testing.T) {
+	ratings := map[string]float64{"A": 1.4, "B": 1.1}
+	homeModel := AdditiveHomeAdvantage(0.3)
+	homeLambda, awayLambda := homeModel.Lambdas(ratings["A"], ratings["B"])
+	fromRatings := NewScoreMatrix("A vs B", ratings, 0.3)
+	fromLambdas := NewScoreMatrixFromLambdas(homeLambda, awayLambda, DefaultRho, DefaultN)
+	if !reflect.DeepEqual(fromLambdas, fromRatings) {
+		t.Errorf("matrix from lambdas %+v differs from matrix from ratings %+v", fromLambdas, fromRatings)
+	}
+
+	clamped := NewScoreMatrixFromLambdas(-0.5, 1.2, 5, 4)
+	if clamped.HomeLambda != LambdaMin || clamped.Rho != RhoMax || len(clamped.Matrix) != 4 {
+		t.Errorf("expected a clamped 4x4 matrix, got lambda %g rho %g size %d", clamped.HomeLambda, clamped.Rho, len(clamped.Matrix))
+	}
+}
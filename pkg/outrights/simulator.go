@@ -1,14 +1,59 @@
 package outrights
 
 import (
+	"fmt"
+	"math"
+	"math/rand"
 	"sort"
 )
 
+// Tie-break keys applied, in order, to teams level on points
+const (
+	TieBreakGoalDifference = "goal_difference"
+	TieBreakGoalsFor       = "goals_for"
+	TieBreakWins           = "wins"
+)
+
+// ValidateTieBreakers checks that every tie-break key is supported
+func ValidateTieBreakers(tieBreakers []string) error {
+	for _, key := range tieBreakers {
+		if key != TieBreakGoalDifference && key != TieBreakGoalsFor && key != TieBreakWins {
+			return fmt.Errorf("unknown tie-break key %s", key)
+		}
+	}
+	return nil
+}
+
+// PointsRule maps a (homeGoals, awayGoals) scoreline to (homePoints, awayPoints)
+type PointsRule func(homeGoals, awayGoals int) (int, int)
+
+// StandardPointsRule awards 3 points for a win, 1 for a draw and 0 for a loss
+func StandardPointsRule(homeGoals, awayGoals int) (int, int) {
+	if homeGoals > awayGoals {
+		return 3, 0
+	} else if homeGoals < awayGoals {
+		return 0, 3
+	}
+	return 1, 1
+}
+
 type SimPoints struct {
 	NPaths         int
 	TeamNames      []string
 	Points         [][]int
 	GoalDifference [][]int
+	GoalsFor       [][]int
+	Wins           [][]int
+	TieBreakers    []string // Keys applied in order to teams level on points
+	PointsRule     PointsRule
+	NoDraws           bool    // Resolve level scorelines with a shootout
+	ShootoutHomeShare float64 // Probability the home side wins a shootout; shootouts award points and a win but no goals
+	Deterministic     bool    // Play every fixture's modal scoreline instead of sampling, for debugging
+	SharedTies        bool    // Split teams level on every tie-break evenly across the positions they span
+	MaxGoals          int     // Cap each side's simulated goals, folding higher scores into the cap; 0 for no cap
+	RatingSigma       float64 // Std dev of each team's per-path rating shock, held across the path's fixtures; 0 disables
+	Rand              *rand.Rand // Random source for sampling; NewSimPoints gives each its own
+	ratingShocks      map[string][]float64 // Per-team, per-path shocks, drawn on each team's first fixture
 }
 
 func NewSimPoints(leagueTable []Team, nPaths int) *SimPoints {
@@ -17,17 +62,27 @@ func NewSimPoints(leagueTable []Team, nPaths int) *SimPoints {
 		TeamNames:      make([]string, len(leagueTable)),
 		Points:         make([][]int, len(leagueTable)),
 		GoalDifference: make([][]int, len(leagueTable)),
+		GoalsFor:       make([][]int, len(leagueTable)),
+		Wins:           make([][]int, len(leagueTable)),
+		TieBreakers:    []string{TieBreakGoalDifference},
+		PointsRule:     StandardPointsRule,
+		ShootoutHomeShare: 0.5,
+		Rand:           NewRand(0),
 	}
 	
 	for i, team := range leagueTable {
 		sp.TeamNames[i] = team.Name
 		sp.Points[i] = make([]int, nPaths)
 		sp.GoalDifference[i] = make([]int, nPaths)
+		sp.GoalsFor[i] = make([]int, nPaths)
+		sp.Wins[i] = make([]int, nPaths)
 		
-		// Initialize with current points and goal difference
+		// Initialize with current points, goal difference, goals scored and wins
 		for j := 0; j < nPaths; j++ {
 			sp.Points[i][j] = team.Points
 			sp.GoalDifference[i][j] = team.GoalDifference
+			sp.GoalsFor[i][j] = team.GoalsFor
+			sp.Wins[i][j] = team.Wins
 		}
 	}
 	
@@ -44,12 +99,56 @@ func (sp *SimPoints) getTeamIndex(teamName string) int {
 }
 
 func (sp *SimPoints) Simulate(eventName string, ratings map[string]float64, homeAdvantage float64) {
-	matrix := NewScoreMatrix(eventName, ratings, homeAdvantage)
-	scores := matrix.simulateScores(sp.NPaths)
+	sp.SimulateWithModel(eventName, ratings, AdditiveHomeAdvantage(homeAdvantage))
+}
+
+// SimulateWithModel simulates eventName on every path under the given home advantage model
+func (sp *SimPoints) SimulateWithModel(eventName string, ratings map[string]float64, homeModel HomeAdvantageModel) {
+	var scores [][]int
+	if sp.Deterministic {
+		scores = homeModel.NewScoreMatrix(eventName, ratings).modalScores(sp.NPaths)
+	} else if sp.RatingSigma > 0 {
+		scores = sp.shockedScores(eventName, ratings, homeModel)
+	} else {
+		scores = homeModel.NewScoreMatrix(eventName, ratings).simulateScores(sp.NPaths, sp.Rand)
+	}
 	sp.updateEvent(eventName, scores)
 }
 
-func (sp *SimPoints) updateHomeTeam(teamName string, scores [][]int) {
+// shockedScores samples eventName on every path with each team's rating moved by its
+// shock for that path, so a team over- or under-performs consistently across a season
+func (sp *SimPoints) shockedScores(eventName string, ratings map[string]float64, homeModel HomeAdvantageModel) [][]int {
+	homeTeam, awayTeam := ParseEventName(eventName)
+	homeShocks, awayShocks := sp.teamRatingShocks(homeTeam), sp.teamRatingShocks(awayTeam)
+	
+	rho := ClampRho(homeModel.dixonColesRho())
+	scores := make([][]int, sp.NPaths)
+	for path := range scores {
+		homeLambda, awayLambda := homeModel.Lambdas(ratings[homeTeam]+homeShocks[path], ratings[awayTeam]+awayShocks[path])
+		homeGoals, awayGoals := sampleScore(homeLambda, awayLambda, rho, DefaultN, sp.Rand)
+		scores[path] = []int{homeGoals, awayGoals}
+	}
+	return scores
+}
+
+// teamRatingShocks returns teamName's rating shock on every path, drawing them on first use
+func (sp *SimPoints) teamRatingShocks(teamName string) []float64 {
+	if sp.ratingShocks == nil {
+		sp.ratingShocks = make(map[string][]float64)
+	}
+	if shocks, exists := sp.ratingShocks[teamName]; exists {
+		return shocks
+	}
+	
+	shocks := make([]float64, sp.NPaths)
+	for path := range shocks {
+		shocks[path] = sp.Rand.NormFloat64() * sp.RatingSigma
+	}
+	sp.ratingShocks[teamName] = shocks
+	return shocks
+}
+
+func (sp *SimPoints) updateHomeTeam(teamName string, scores [][]int, shootouts []int) {
 	teamIndex := sp.getTeamIndex(teamName)
 	if teamIndex == -1 {
 		return
@@ -58,25 +157,25 @@ func (sp *SimPoints) updateHomeTeam(teamName string, scores [][]int) {
 	for i, score := range scores {
 		homeGoals := score[0]
 		awayGoals := score[1]
+		decidedHome, decidedAway := decidedScore(score, shootouts, i)
 		
 		// Calculate points
-		points := 0
-		if homeGoals > awayGoals {
-			points = 3
-		} else if homeGoals == awayGoals {
-			points = 1
-		}
+		points, _ := sp.PointsRule(decidedHome, decidedAway)
 		
 		// Calculate goal difference
 		goalDifference := homeGoals - awayGoals
 		
-		// Update points and goal difference separately
+		// Update points, goal difference, goals scored and wins separately
 		sp.Points[teamIndex][i] += points
 		sp.GoalDifference[teamIndex][i] += goalDifference
+		sp.GoalsFor[teamIndex][i] += homeGoals
+		if decidedHome > decidedAway {
+			sp.Wins[teamIndex][i]++
+		}
 	}
 }
 
-func (sp *SimPoints) updateAwayTeam(teamName string, scores [][]int) {
+func (sp *SimPoints) updateAwayTeam(teamName string, scores [][]int, shootouts []int) {
 	teamIndex := sp.getTeamIndex(teamName)
 	if teamIndex == -1 {
 		return
@@ -85,28 +184,53 @@ func (sp *SimPoints) updateAwayTeam(teamName string, scores [][]int) {
 	for i, score := range scores {
 		homeGoals := score[0]
 		awayGoals := score[1]
+		decidedHome, decidedAway := decidedScore(score, shootouts, i)
 		
 		// Calculate points
-		points := 0
-		if awayGoals > homeGoals {
-			points = 3
-		} else if homeGoals == awayGoals {
-			points = 1
-		}
+		_, points := sp.PointsRule(decidedHome, decidedAway)
 		
 		// Calculate goal difference
 		goalDifference := awayGoals - homeGoals
 		
-		// Update points and goal difference separately
+		// Update points, goal difference, goals scored and wins separately
 		sp.Points[teamIndex][i] += points
 		sp.GoalDifference[teamIndex][i] += goalDifference
+		sp.GoalsFor[teamIndex][i] += awayGoals
+		if decidedAway > decidedHome {
+			sp.Wins[teamIndex][i]++
+		}
 	}
 }
 
 func (sp *SimPoints) updateEvent(eventName string, scores [][]int) {
+	if sp.MaxGoals > 0 {
+		capScores(scores, sp.MaxGoals)
+	}
+	
+	var shootouts []int
+	if sp.NoDraws {
+		homeShare := sp.ShootoutHomeShare
+		if sp.Deterministic {
+			// Award every shootout to the likelier winner so paths stay identical
+			homeShare = math.Round(homeShare)
+		}
+		shootouts = resolveDraws(scores, homeShare, sp.Rand)
+	}
+	
 	homeTeam, awayTeam := ParseEventName(eventName)
-	sp.updateHomeTeam(homeTeam, scores)
-	sp.updateAwayTeam(awayTeam, scores)
+	sp.updateHomeTeam(homeTeam, scores, shootouts)
+	sp.updateAwayTeam(awayTeam, scores, shootouts)
+}
+
+// capScores clamps each side's goals to maxGoals in place, so the tail mass lands on the cap
+func capScores(scores [][]int, maxGoals int) {
+	for _, score := range scores {
+		for side := range score {
+			if score[side] > maxGoals {
+				score[side] = maxGoals
+			}
+		}
+	}
 }
 
 func (sp *SimPoints) positionProbabilities(teamNames []string) map[string][]float64 {
@@ -126,48 +250,12 @@ func (sp *SimPoints) positionProbabilities(teamNames []string) map[string][]floa
 		return make(map[string][]float64)
 	}
 	
-	// Extract points and goal difference for selected teams
-	selectedPoints := make([][]int, len(selectedIndices))
-	selectedGoalDifference := make([][]int, len(selectedIndices))
-	for i, idx := range selectedIndices {
-		selectedPoints[i] = sp.Points[idx]
-		selectedGoalDifference[i] = sp.GoalDifference[idx]
-	}
-	
-	// Calculate positions for each path
-	positions := make([][]int, len(selectedIndices))
-	for i := range positions {
-		positions[i] = make([]int, sp.NPaths)
-	}
-	
-	for path := 0; path < sp.NPaths; path++ {
-		// Create array of team data for this path
-		teamData := make([]struct {
-			TeamIndex    int
-			CombinedScore float64
-		}, len(selectedIndices))
-		
-		for i := range selectedIndices {
-			// Combine points with goal difference as tie-breaker (multiply by small factor)
-			combinedScore := float64(selectedPoints[i][path]) + float64(selectedGoalDifference[i][path])*0.001
-			teamData[i] = struct {
-				TeamIndex    int
-				CombinedScore float64
-			}{
-				TeamIndex:    i,
-				CombinedScore: combinedScore,
-			}
-		}
-		
-		// Sort by combined score (descending) to get positions
-		sort.Slice(teamData, func(i, j int) bool {
-			return teamData[i].CombinedScore > teamData[j].CombinedScore
-		})
-		
-		// Assign positions (0 = first place, 1 = second place, etc.)
-		for pos, team := range teamData {
-			positions[team.TeamIndex][path] = pos
-		}
+	var positions [][]int
+	var sharedProbs [][]float64
+	if sp.SharedTies {
+		sharedProbs = sp.calcSharedPositionProbabilities(selectedIndices)
+	} else {
+		positions = sp.calcPositions(selectedIndices)
 	}
 	
 	// Calculate probabilities
@@ -185,7 +273,9 @@ func (sp *SimPoints) positionProbabilities(teamNames []string) map[string][]floa
 				}
 			}
 			
-			if selectedIdx >= 0 {
+			if selectedIdx >= 0 && sharedProbs != nil {
+				copy(probs, sharedProbs[selectedIdx])
+			} else if selectedIdx >= 0 {
 				// Count occurrences of each position
 				for path := 0; path < sp.NPaths; path++ {
 					pos := positions[selectedIdx][path]
@@ -200,6 +290,310 @@ func (sp *SimPoints) positionProbabilities(teamNames []string) map[string][]floa
 	return probabilities
 }
 
+// calcPositions calculates each selected team's finishing position (0 = first) within
+// the selection on every path
+func (sp *SimPoints) calcPositions(selectedIndices []int) [][]int {
+	// Calculate positions for each path
+	positions := make([][]int, len(selectedIndices))
+	for i := range positions {
+		positions[i] = make([]int, sp.NPaths)
+	}
+	
+	order := make([]int, len(selectedIndices))
+	for path := 0; path < sp.NPaths; path++ {
+		for i := range order {
+			order[i] = i
+		}
+		
+		// Sort by points, then tie-break keys in order, to get positions
+		sort.Slice(order, func(a, b int) bool {
+			return sp.ranksAbove(selectedIndices[order[a]], selectedIndices[order[b]], path)
+		})
+		
+		// Assign positions (0 = first place, 1 = second place, etc.)
+		for pos, i := range order {
+			positions[i][path] = pos
+		}
+	}
+	
+	return positions
+}
+
+// calcSharedPositionProbabilities calculates each selected team's position probabilities
+// within the selection, splitting each path's weight evenly across the positions spanned
+// by teams that can't be separated on points or any tie-break key
+func (sp *SimPoints) calcSharedPositionProbabilities(selectedIndices []int) [][]float64 {
+	probs := make([][]float64, len(selectedIndices))
+	for i := range probs {
+		probs[i] = make([]float64, len(selectedIndices))
+	}
+	
+	order := make([]int, len(selectedIndices))
+	for path := 0; path < sp.NPaths; path++ {
+		for i := range order {
+			order[i] = i
+		}
+		sort.Slice(order, func(a, b int) bool {
+			return sp.ranksAbove(selectedIndices[order[a]], selectedIndices[order[b]], path)
+		})
+		
+		// Walk runs of exactly tied teams, each sharing the run's positions
+		for start := 0; start < len(order); {
+			end := start + 1
+			for end < len(order) && !sp.ranksAbove(selectedIndices[order[end-1]], selectedIndices[order[end]], path) {
+				end++
+			}
+			share := 1.0 / float64(sp.NPaths) / float64(end-start)
+			for _, i := range order[start:end] {
+				for pos := start; pos < end; pos++ {
+					probs[i][pos] += share
+				}
+			}
+			start = end
+		}
+	}
+	
+	return probs
+}
+
+// PathPositions returns each team's finishing position (0 = first) within the given
+// subset of teams on every path; nil means all teams
+func (sp *SimPoints) PathPositions(teamNames []string) map[string][]int {
+	if teamNames == nil {
+		teamNames = sp.TeamNames
+	}
+	
+	selectedIndices := make([]int, 0, len(teamNames))
+	for _, name := range teamNames {
+		if idx := sp.getTeamIndex(name); idx >= 0 {
+			selectedIndices = append(selectedIndices, idx)
+		}
+	}
+	
+	positions := sp.calcPositions(selectedIndices)
+	
+	pathPositions := make(map[string][]int)
+	for i, idx := range selectedIndices {
+		pathPositions[sp.TeamNames[idx]] = positions[i]
+	}
+	return pathPositions
+}
+
+// ranksAbove reports whether team i finishes above team j on the given path, comparing
+// points and then each tie-break key in order via rankAbove
+func (sp *SimPoints) ranksAbove(i, j, path int) bool {
+	return rankAbove(sp.tableRow(i, path), sp.tableRow(j, path), sp.TieBreakers)
+}
+
+// tableRow returns team i's line in the final table of the given path
+func (sp *SimPoints) tableRow(i, path int) TableRow {
+	return TableRow{
+		Team:           sp.TeamNames[i],
+		Points:         sp.Points[i][path],
+		GoalDifference: sp.GoalDifference[i][path],
+		GoalsFor:       sp.GoalsFor[i][path],
+		Wins:           sp.Wins[i][path],
+	}
+}
+
+// PositionProbabilities calculates finishing position probabilities within the given
+// subset of teams; nil means all teams
+func (sp *SimPoints) PositionProbabilities(teamNames []string) map[string][]float64 {
+	return sp.positionProbabilities(teamNames)
+}
+
+// SampleTables returns the complete final tables, ranked with the tie-break rules, of k
+// randomly chosen paths (without replacement; capped at NPaths)
+func (sp *SimPoints) SampleTables(k int) [][]TableRow {
+	if k > sp.NPaths {
+		k = sp.NPaths
+	} else if k < 0 {
+		k = 0
+	}
+	
+	tables := make([][]TableRow, 0, k)
+	for _, path := range sp.Rand.Perm(sp.NPaths)[:k] {
+		order := make([]int, len(sp.TeamNames))
+		for i := range order {
+			order[i] = i
+		}
+		sort.Slice(order, func(a, b int) bool {
+			return sp.ranksAbove(order[a], order[b], path)
+		})
+		
+		table := make([]TableRow, len(order))
+		for pos, i := range order {
+			table[pos] = sp.tableRow(i, path)
+		}
+		tables = append(tables, table)
+	}
+	
+	return tables
+}
+
+// MostGoalsProbabilities calculates, per team, the probability of finishing the season
+// with the most goals scored; teams level on goals share the path as a dead heat
+func (sp *SimPoints) MostGoalsProbabilities(teamNames []string) map[string]float64 {
+	if teamNames == nil {
+		teamNames = sp.TeamNames
+	}
+	
+	selectedIndices := make([]int, 0, len(teamNames))
+	for _, name := range teamNames {
+		if idx := sp.getTeamIndex(name); idx >= 0 {
+			selectedIndices = append(selectedIndices, idx)
+		}
+	}
+	
+	shares := make([]float64, len(selectedIndices))
+	for path := 0; path < sp.NPaths; path++ {
+		maxGoals := math.MinInt
+		nLeaders := 0
+		for _, idx := range selectedIndices {
+			if goals := sp.GoalsFor[idx][path]; goals > maxGoals {
+				maxGoals = goals
+				nLeaders = 1
+			} else if goals == maxGoals {
+				nLeaders++
+			}
+		}
+		for i, idx := range selectedIndices {
+			if sp.GoalsFor[idx][path] == maxGoals {
+				shares[i] += 1.0 / float64(nLeaders)
+			}
+		}
+	}
+	
+	probabilities := make(map[string]float64, len(selectedIndices))
+	for i, idx := range selectedIndices {
+		probabilities[sp.TeamNames[idx]] = shares[i] / float64(sp.NPaths)
+	}
+	return probabilities
+}
+
+// PointsDistribution calculates the probability of each final points total for team
+// across the simulated paths; unknown teams get an empty map
+func (sp *SimPoints) PointsDistribution(team string) map[int]float64 {
+	distribution := make(map[int]float64)
+	idx := sp.getTeamIndex(team)
+	if idx == -1 || sp.NPaths == 0 {
+		return distribution
+	}
+	
+	for _, points := range sp.Points[idx] {
+		distribution[points]++
+	}
+	for points := range distribution {
+		distribution[points] /= float64(sp.NPaths)
+	}
+	return distribution
+}
+
+// RelativeFinish calculates the probability team a finishes anywhere above team b in the
+// full table, and the probability it finishes directly above b; unknown teams get zeros
+func (sp *SimPoints) RelativeFinish(a, b string) (aboveProb, adjacentProb float64) {
+	if sp.getTeamIndex(a) == -1 || sp.getTeamIndex(b) == -1 || sp.NPaths == 0 {
+		return 0, 0
+	}
+	
+	positions := sp.PathPositions(nil)
+	for path := 0; path < sp.NPaths; path++ {
+		posA, posB := positions[a][path], positions[b][path]
+		if posA < posB {
+			aboveProb++
+		}
+		if posA == posB-1 {
+			adjacentProb++
+		}
+	}
+	return aboveProb / float64(sp.NPaths), adjacentProb / float64(sp.NPaths)
+}
+
+// GoalDifferenceTieBreaks calculates, per team, the fraction of paths in which it finished
+// level on points with an adjacent team and goal difference decided the order between them
+func (sp *SimPoints) GoalDifferenceTieBreaks(teamNames []string) map[string]float64 {
+	if teamNames == nil {
+		teamNames = sp.TeamNames
+	}
+	
+	selectedIndices := make([]int, 0, len(teamNames))
+	for _, name := range teamNames {
+		if idx := sp.getTeamIndex(name); idx >= 0 {
+			selectedIndices = append(selectedIndices, idx)
+		}
+	}
+	
+	counts := make([]int, len(selectedIndices))
+	order := make([]int, len(selectedIndices))
+	goalDifferenceOnly := []string{TieBreakGoalDifference}
+	
+	for path := 0; path < sp.NPaths; path++ {
+		for i := range order {
+			order[i] = i
+		}
+		
+		// Rank by points, then goal difference
+		sort.Slice(order, func(a, b int) bool {
+			ia, ib := selectedIndices[order[a]], selectedIndices[order[b]]
+			return rankAbove(sp.tableRow(ia, path), sp.tableRow(ib, path), goalDifferenceOnly)
+		})
+		
+		// Flag each team level on points with a neighbour but separated by goal difference
+		decided := make([]bool, len(order))
+		for pos := 1; pos < len(order); pos++ {
+			above, below := selectedIndices[order[pos-1]], selectedIndices[order[pos]]
+			if sp.Points[above][path] == sp.Points[below][path] &&
+				sp.GoalDifference[above][path] != sp.GoalDifference[below][path] {
+				decided[pos-1] = true
+				decided[pos] = true
+			}
+		}
+		for pos, flag := range decided {
+			if flag {
+				counts[order[pos]]++
+			}
+		}
+	}
+	
+	rates := make(map[string]float64)
+	for i, idx := range selectedIndices {
+		rates[sp.TeamNames[idx]] = float64(counts[i]) / float64(sp.NPaths)
+	}
+	
+	return rates
+}
+
+// CheckExpectedPoints compares each team's mean simulated points against the deterministic
+// model, flagging teams whose difference exceeds nSigma Monte Carlo standard errors
+// Custom points rules or shootouts legitimately diverge from the 3/1/0 deterministic model
+func (sp *SimPoints) CheckExpectedPoints(deterministic map[string]float64, nSigma float64) []PointsCheck {
+	checks := make([]PointsCheck, 0, len(sp.TeamNames))
+	
+	for i, name := range sp.TeamNames {
+		sum, sumSquares := 0.0, 0.0
+		for path := 0; path < sp.NPaths; path++ {
+			points := float64(sp.Points[i][path])
+			sum += points
+			sumSquares += points * points
+		}
+		mean := sum / float64(sp.NPaths)
+		variance := math.Max(0, sumSquares/float64(sp.NPaths)-mean*mean)
+		
+		// Small absolute floor so a team with nothing left to play is not flagged on rounding
+		tolerance := nSigma*math.Sqrt(variance/float64(sp.NPaths)) + 1e-6
+		
+		checks = append(checks, PointsCheck{
+			Team:          name,
+			Simulated:     mean,
+			Deterministic: deterministic[name],
+			Tolerance:     tolerance,
+			Flagged:       math.Abs(mean-deterministic[name]) > tolerance,
+		})
+	}
+	
+	return checks
+}
+
 // GetSimulationData returns the simulation data needed for external calculations
 func (sp *SimPoints) GetSimulationData() (teamNames []string, points [][]int, nPaths int) {
 	return sp.TeamNames, sp.Points, sp.NPaths
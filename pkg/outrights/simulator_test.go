@@ -0,0 +1,365 @@
+package outrights
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// newTestSimPoints creates seeded paths for two teams level on zero
+func newTestSimPoints(nPaths int) *SimPoints {
+	sp := NewSimPoints([]Team{{Name: "A"}, {Name: "B"}}, nPaths)
+	sp.Rand = rand.New(rand.NewSource(1))
+	return sp
+}
+
+func TestNoDrawsShootouts(t *testing.T) {
+	ratings := map[string]float64{"A": 1.2, "B": 1.2}
+	homeModel := HomeAdvantageModel{HomeAdvantage: 0.1}
+
+	tests := []struct {
+		name      string
+		homeShare float64
+		maxGoals  int
+	}{
+		{"even shootouts", 0.5, 0},
+		{"away always wins", 0, 0},
+		{"home always wins with capped goals", 1, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := newTestSimPoints(2000)
+			sp.NoDraws = true
+			sp.ShootoutHomeShare = tt.homeShare
+			sp.MaxGoals = tt.maxGoals
+			sp.SimulateWithModel("A vs B", ratings, homeModel)
+
+			shootouts := 0
+			for path := 0; path < sp.NPaths; path++ {
+				homePoints, awayPoints := sp.Points[0][path], sp.Points[1][path]
+				if homePoints == awayPoints {
+					t.Fatalf("path %d ended level on %d points", path, homePoints)
+				}
+				if sp.Wins[0][path]+sp.Wins[1][path] != 1 {
+					t.Fatalf("path %d has %d wins, want 1", path, sp.Wins[0][path]+sp.Wins[1][path])
+				}
+
+				// Goals stay as played, so a shootout leaves goal difference level
+				if sp.GoalDifference[0][path] != sp.GoalsFor[0][path]-sp.GoalsFor[1][path] {
+					t.Fatalf("path %d goal difference doesn't match goals for", path)
+				}
+				if tt.maxGoals > 0 && (sp.GoalsFor[0][path] > tt.maxGoals || sp.GoalsFor[1][path] > tt.maxGoals) {
+					t.Fatalf("path %d exceeds MaxGoals %d", path, tt.maxGoals)
+				}
+				if sp.GoalDifference[0][path] == 0 {
+					shootouts++
+					homeWon := homePoints > awayPoints
+					if (tt.homeShare == 0 && homeWon) || (tt.homeShare == 1 && !homeWon) {
+						t.Fatalf("path %d shootout went against a home share of %g", path, tt.homeShare)
+					}
+				}
+			}
+			if shootouts == 0 {
+				t.Error("expected some level scorelines to go to shootouts")
+			}
+		})
+	}
+}
+
+func TestRedistributeDraw(t *testing.T) {
+	for _, homeShare := range []float64{0, 0.3, 0.5, 1} {
+		probs := RedistributeDraw([]float64{0.45, 0.25, 0.3}, homeShare)
+		if probs[1] != 0 {
+			t.Errorf("share %g: draw probability %g, want 0", homeShare, probs[1])
+		}
+		if sum := probs[0] + probs[1] + probs[2]; math.Abs(sum-1) > 1e-12 {
+			t.Errorf("share %g: probabilities sum to %g", homeShare, sum)
+		}
+		if want := 0.45 + 0.25*homeShare; math.Abs(probs[0]-want) > 1e-12 {
+			t.Errorf("share %g: home probability %g, want %g", homeShare, probs[0], want)
+		}
+	}
+}
+
+func TestRatingSigmaWidensPoints(t *testing.T) {
+	ratings := map[string]float64{"A": 1.2, "B": 1.2}
+	homeModel := HomeAdvantageModel{HomeAdvantage: 0.1}
+
+	pointsStdDev := func(ratingSigma float64) float64 {
+		sp := newTestSimPoints(4000)
+		sp.RatingSigma = ratingSigma
+		for i := 0; i < 10; i++ {
+			sp.SimulateWithModel("A vs B", ratings, homeModel)
+			sp.SimulateWithModel("B vs A", ratings, homeModel)
+		}
+		points := make([]float64, sp.NPaths)
+		for path := range points {
+			points[path] = float64(sp.Points[0][path])
+		}
+		return StdDev(points)
+	}
+
+	// A shock held across a path's fixtures correlates its results, widening the spread
+	base, shocked := pointsStdDev(0), pointsStdDev(0.5)
+	if shocked < base*1.2 {
+		t.Errorf("points std dev %.3f with RatingSigma vs %.3f without, want clearly wider", shocked, base)
+	}
+}
+
+func TestCustomPointsRule(t *testing.T) {
+	ratings := map[string]float64{"A": 2.2, "B": 1.8}
+	homeModel := HomeAdvantageModel{HomeAdvantage: 0.3}
+	bonusRule := func(homeGoals, awayGoals int) (int, int) {
+		homePoints, awayPoints := StandardPointsRule(homeGoals, awayGoals)
+		if homeGoals >= 4 {
+			homePoints++
+		}
+		if awayGoals >= 4 {
+			awayPoints++
+		}
+		return homePoints, awayPoints
+	}
+
+	simulate := func(rule PointsRule) *SimPoints {
+		sp := newTestSimPoints(2000)
+		sp.PointsRule = rule
+		sp.SimulateWithModel("A vs B", ratings, homeModel)
+		sp.SimulateWithModel("B vs A", ratings, homeModel)
+		return sp
+	}
+	standard, bonus := simulate(StandardPointsRule), simulate(bonusRule)
+
+	// Same seed, same scorelines: bonus points can only add
+	for team := range standard.Points {
+		total, bonusTotal := 0, 0
+		for path := 0; path < standard.NPaths; path++ {
+			if bonus.Points[team][path] < standard.Points[team][path] {
+				t.Fatalf("team %d path %d: bonus rule gave %d points, standard %d", team, path, bonus.Points[team][path], standard.Points[team][path])
+			}
+			total += standard.Points[team][path]
+			bonusTotal += bonus.Points[team][path]
+		}
+		if bonusTotal <= total {
+			t.Errorf("team %d: bonus total %d not above standard %d", team, bonusTotal, total)
+		}
+	}
+}
+
+func TestDeterministicTableRepeats(t *testing.T) {
+	teams, fixtures, ratings := smallLeague()
+	homeModel := AdditiveHomeAdvantage(0.3)
+	simulate := func(seed int64) *SimPoints {
+		sp := NewSimPoints(teams, 50)
+		sp.Rand = rand.New(rand.NewSource(seed))
+		sp.Deterministic = true
+		for _, fixture := range fixtures {
+			sp.SimulateWithModel(fixture, ratings, homeModel)
+		}
+		return sp
+	}
+
+	first, second := simulate(1), simulate(2)
+	for team := range first.Points {
+		for path := 0; path < first.NPaths; path++ {
+			if first.Points[team][path] != first.Points[team][0] || first.GoalDifference[team][path] != first.GoalDifference[team][0] {
+				t.Fatalf("team %d path %d differs from path 0", team, path)
+			}
+			if first.Points[team][path] != second.Points[team][path] || first.GoalDifference[team][path] != second.GoalDifference[team][path] {
+				t.Fatalf("team %d path %d differs between runs", team, path)
+			}
+		}
+	}
+	names := []string{"A", "B", "C", "D"}
+	if !reflect.DeepEqual(first.PositionProbabilities(names), second.PositionProbabilities(names)) {
+		t.Error("position probabilities differ between runs")
+	}
+}
+
+func TestMaxGoalsCapsScores(t *testing.T) {
+	ratings := map[string]float64{"A": 3.0, "B": 2.5}
+	sp := newTestSimPoints(2000)
+	sp.MaxGoals = 2
+	sp.SimulateWithModel("A vs B", ratings, HomeAdvantageModel{HomeAdvantage: 0.3})
+
+	capped := 0
+	for path := 0; path < sp.NPaths; path++ {
+		homeGoals, awayGoals := sp.GoalsFor[0][path], sp.GoalsFor[1][path]
+		if homeGoals > sp.MaxGoals || awayGoals > sp.MaxGoals {
+			t.Fatalf("path %d scored %d-%d above the cap of %d", path, homeGoals, awayGoals, sp.MaxGoals)
+		}
+		if homeGoals == sp.MaxGoals {
+			capped++
+		}
+	}
+
+	// With these lambdas most of the home side's mass is at or above the cap
+	if capped < sp.NPaths/2 {
+		t.Errorf("only %d of %d paths at the cap, want the tail folded onto it", capped, sp.NPaths)
+	}
+}
+
+// handPaths returns four hand-built paths for teams A, B and C, finishing A-B-C, B-A-C,
+// B-A-C and A-C-B, with goal difference separating the teams level on points
+func handPaths() *SimPoints {
+	sp := NewSimPoints([]Team{{Name: "A"}, {Name: "B"}, {Name: "C"}}, 4)
+	sp.Rand = rand.New(rand.NewSource(1))
+	sp.Points = [][]int{{10, 10, 5, 5}, {5, 10, 10, 1}, {1, 1, 5, 5}}
+	sp.GoalDifference = [][]int{{3, 2, -1, 4}, {0, 5, 6, -3}, {-3, -7, -5, -1}}
+	sp.GoalsFor = [][]int{{5, 4, 3, 6}, {5, 6, 7, 2}, {2, 1, 3, 6}}
+	return sp
+}
+
+func TestMostGoalsProbabilities(t *testing.T) {
+	sp := handPaths()
+	tests := []struct {
+		name  string
+		teams []string
+		want  map[string]float64
+	}{
+		{"all teams with dead heats", nil, map[string]float64{"A": 0.25, "B": 0.625, "C": 0.125}},
+		{"subset", []string{"A", "C"}, map[string]float64{"A": 0.75, "C": 0.25}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sp.MostGoalsProbabilities(tt.teams)
+			total := 0.0
+			for team, want := range tt.want {
+				if math.Abs(got[team]-want) > 1e-12 {
+					t.Errorf("%s = %g, want %g", team, got[team], want)
+				}
+				total += got[team]
+			}
+			if math.Abs(total-1) > 1e-12 {
+				t.Errorf("shares sum to %g, want 1", total)
+			}
+		})
+	}
+}
+
+func TestRelativeFinish(t *testing.T) {
+	sp := handPaths()
+	tests := []struct {
+		a, b                    string
+		wantAbove, wantAdjacent float64
+	}{
+		{"A", "B", 0.5, 0.25},
+		{"B", "A", 0.5, 0.5},
+		{"A", "C", 1, 0.75},
+		{"C", "B", 0.25, 0.25},
+		{"A", "Z", 0, 0},
+	}
+	for _, tt := range tests {
+		above, adjacent := sp.RelativeFinish(tt.a, tt.b)
+		if math.Abs(above-tt.wantAbove) > 1e-12 || math.Abs(adjacent-tt.wantAdjacent) > 1e-12 {
+			t.Errorf("RelativeFinish(%s, %s) = %g, %g, want %g, %g", tt.a, tt.b, above, adjacent, tt.wantAbove, tt.wantAdjacent)
+		}
+	}
+}
+
+func TestGoalDifferenceTieBreaks(t *testing.T) {
+	sp := handPaths()
+	tests := []struct {
+		name  string
+		teams []string
+		want  map[string]float64
+	}{
+		{"all teams", nil, map[string]float64{"A": 0.75, "B": 0.25, "C": 0.5}},
+		{"never level", []string{"B", "C"}, map[string]float64{"B": 0, "C": 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sp.GoalDifferenceTieBreaks(tt.teams)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Teams level on points and goal difference aren't decided by it
+	sp.GoalDifference[1][1] = sp.GoalDifference[0][1]
+	if got := sp.GoalDifferenceTieBreaks(nil)["B"]; got != 0 {
+		t.Errorf("B decided by goal difference on %g of paths with equal goal difference, want 0", got)
+	}
+}
+
+func TestPointsDistribution(t *testing.T) {
+	sp := handPaths()
+	for team, want := range map[string]map[int]float64{
+		"A": {10: 0.5, 5: 0.5},
+		"B": {1: 0.25, 5: 0.25, 10: 0.5},
+		"Z": {},
+	} {
+		got := sp.PointsDistribution(team)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %v, want %v", team, got, want)
+		}
+		total := 0.0
+		for _, prob := range got {
+			total += prob
+		}
+		if len(got) > 0 && math.Abs(total-1) > 1e-12 {
+			t.Errorf("%s: distribution sums to %g", team, total)
+		}
+	}
+}
+
+func TestCheckExpectedPoints(t *testing.T) {
+	sp := handPaths()
+
+	// A averages 7.5 with a standard error of 1.25, so 3 sigma allows 3.75 either way
+	deterministic := map[string]float64{"A": 7.5, "B": 14, "C": 3}
+	flagged := map[string]bool{"A": false, "B": true, "C": false}
+	checks := sp.CheckExpectedPoints(deterministic, 3)
+	if len(checks) != 3 {
+		t.Fatalf("got %d checks, want one per team", len(checks))
+	}
+	for _, check := range checks {
+		if check.Flagged != flagged[check.Team] {
+			t.Errorf("%s flagged %t (simulated %.3f, deterministic %.3f, tolerance %.3f), want %t",
+				check.Team, check.Flagged, check.Simulated, check.Deterministic, check.Tolerance, flagged[check.Team])
+		}
+	}
+	if checks[0].Team != "A" || math.Abs(checks[0].Simulated-7.5) > 1e-12 || math.Abs(checks[0].Tolerance-(3.75+1e-6)) > 1e-12 {
+		t.Errorf("A check %+v, want mean 7.5 and tolerance 3.75", checks[0])
+	}
+
+	// A team with nothing left to play isn't flagged on rounding alone
+	sp.Points[2] = []int{4, 4, 4, 4}
+	if check := sp.CheckExpectedPoints(map[string]float64{"C": 4 + 1e-9}, 3)[2]; check.Flagged {
+		t.Errorf("constant points flagged: %+v", check)
+	}
+}
+
+func TestSampleTables(t *testing.T) {
+	sp := handPaths()
+	tables := sp.SampleTables(10)
+	if len(tables) != 4 {
+		t.Fatalf("expected samples capped at 4 paths, got %d", len(tables))
+	}
+
+	// Sampling every path without replacement sees each path's final table once
+	orders := make(map[string]int)
+	for _, table := range tables {
+		order := ""
+		for pos, row := range table {
+			order += row.Team
+			if pos > 0 && row.Points > table[pos-1].Points {
+				t.Errorf("%s on %d points ranked below %s on %d", row.Team, row.Points, table[pos-1].Team, table[pos-1].Points)
+			}
+		}
+		orders[order]++
+	}
+	want := map[string]int{"ABC": 1, "BAC": 2, "ACB": 1}
+	if !reflect.DeepEqual(orders, want) {
+		t.Errorf("sampled table orders %v, want %v", orders, want)
+	}
+
+	tables = sp.SampleTables(1)
+	if len(tables) != 1 || len(tables[0]) != 3 {
+		t.Errorf("expected one three-team table, got %v", tables)
+	}
+	if got := sp.SampleTables(-1); len(got) != 0 {
+		t.Errorf("expected no tables for a negative count, got %d", len(got))
+	}
+}
@@ -13,8 +13,13 @@ const (
 	RatingMax = 6.0
 	HomeAdvantageMin = 0.0
 	HomeAdvantageMax = 1.5
+	HomeMultiplierMin = 0.5
+	HomeMultiplierMax = 2.0
 )
 
+// NormalizedRatingMean is the mean reported ratings are centred on, the midpoint of the bounds
+const NormalizedRatingMean = (RatingMin + RatingMax) / 2
+
 type GeneticAlgorithm struct {
 	maxIterations       int
 	populationSize      int
@@ -25,6 +30,8 @@ type GeneticAlgorithm struct {
 	decayExponent       float64
 	mutationProbability float64
 	debug               bool
+	onGeneration        func(generation int, best []float64, fitness float64) // Called after each generation, if set
+	rng                 *rand.Rand // Random source, only drawn from on the calling goroutine
 }
 
 type Individual struct {
@@ -38,7 +45,7 @@ func (p Population) Len() int           { return len(p) }
 func (p Population) Less(i, j int) bool { return p[i].Fitness < p[j].Fitness }
 func (p Population) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
 
-func newGeneticAlgorithm(options map[string]interface{}) *GeneticAlgorithm {
+func newGeneticAlgorithm(options map[string]interface{}, rng *rand.Rand) *GeneticAlgorithm {
 	ga := &GeneticAlgorithm{
 		maxIterations:       options["generations"].(int),
 		populationSize:      options["population_size"].(int),
@@ -49,6 +56,7 @@ func newGeneticAlgorithm(options map[string]interface{}) *GeneticAlgorithm {
 		decayExponent:       options["decay_exponent"].(float64),
 		mutationProbability: options["mutation_probability"].(float64),
 		debug:               options["debug"].(bool),
+		rng:                 rng,
 	}
 	return ga
 }
@@ -56,6 +64,7 @@ func newGeneticAlgorithm(options map[string]interface{}) *GeneticAlgorithm {
 func (ga *GeneticAlgorithm) optimize(objectiveFn func([]float64) float64, x0 []float64, bounds [][]float64) ([]float64, float64) {
 	nParams := len(x0)
 	nElite := int(math.Max(1, float64(ga.populationSize)*ga.eliteRatio))
+	rng := ga.rng
 	
 	log.Printf("Starting parallel genetic algorithm: %d generations, %d candidates per generation", ga.maxIterations, ga.populationSize)
 	
@@ -73,9 +82,9 @@ func (ga *GeneticAlgorithm) optimize(objectiveFn func([]float64) float64, x0 []f
 		genes := make([]float64, nParams)
 		for j := 0; j < nParams; j++ {
 			if bounds != nil && len(bounds[j]) == 2 {
-				genes[j] = bounds[j][0] + rand.Float64()*(bounds[j][1]-bounds[j][0])
+				genes[j] = bounds[j][0] + rng.Float64()*(bounds[j][1]-bounds[j][0])
 			} else {
-				genes[j] = x0[j] + rand.NormFloat64()*ga.initStd
+				genes[j] = x0[j] + rng.NormFloat64()*ga.initStd
 			}
 		}
 		population[i] = Individual{Genes: genes}
@@ -88,9 +97,11 @@ func (ga *GeneticAlgorithm) optimize(objectiveFn func([]float64) float64, x0 []f
 		// Evaluate fitness in parallel
 		var wg sync.WaitGroup
 		for i := range population {
+			release := acquireWorker()
 			wg.Add(1)
 			go func(idx int) {
 				defer wg.Done()
+				defer release()
 				population[idx].Fitness = objectiveFn(population[idx].Genes)
 			}(i)
 		}
@@ -121,6 +132,10 @@ func (ga *GeneticAlgorithm) optimize(objectiveFn func([]float64) float64, x0 []f
 				generation+1, ga.maxIterations, bestFitness, avgFitness, currentMutation)
 		}
 		
+		if ga.onGeneration != nil {
+			ga.onGeneration(generation+1, bestSolution, bestFitness)
+		}
+		
 		
 		// Create new population
 		newPopulation := make(Population, ga.populationSize)
@@ -141,7 +156,7 @@ func (ga *GeneticAlgorithm) optimize(objectiveFn func([]float64) float64, x0 []f
 		
 		for i := nElite; i < ga.populationSize; i++ {
 			// Select random elite parent
-			parentIdx := rand.Intn(nElite)
+			parentIdx := rng.Intn(nElite)
 			parent := population[parentIdx]
 			
 			// Create offspring
@@ -152,8 +167,8 @@ func (ga *GeneticAlgorithm) optimize(objectiveFn func([]float64) float64, x0 []f
 			
 			// Apply mutations
 			for j := 0; j < nParams; j++ {
-				if rand.Float64() < ga.mutationProbability {
-					mutation := rand.NormFloat64() * currentMutationFactor
+				if rng.Float64() < ga.mutationProbability {
+					mutation := rng.NormFloat64() * currentMutationFactor
 					offspring.Genes[j] += mutation
 					
 					// Clamp to bounds
@@ -173,7 +188,19 @@ func (ga *GeneticAlgorithm) optimize(objectiveFn func([]float64) float64, x0 []f
 	return bestSolution, bestFitness
 }
 
-type RatingsSolver struct{}
+type RatingsSolver struct {
+	marginMethod   string
+	formHalfLife   float64   // Games after which a result's weight halves in the form-weighted init table; 0 disables
+	xgWeight       float64   // Weight of the lambda vs observed xG error for events carrying xG; 0 ignores xG
+	outcomeWeights []float64 // Weights of the home, draw and away terms in the 1X2 error; nil weights them equally
+	priorRatings   map[string]float64 // Ratings the prior penalty pulls toward
+	priorWeight    float64   // Weight of the RMS deviation from priorRatings; 0 disables
+	restarts       int       // Independent GA runs per fit, keeping the best; 0 or 1 runs once
+	rng            *rand.Rand // Random source for the run, seeded from the "seed" option
+	rho            *float64  // Dixon-Coles rho for every model fitted; nil uses DefaultRho
+	traceEnabled   bool
+	trace          []SolverTraceEntry
+}
 
 func NewRatingsSolver() *RatingsSolver {
 	return &RatingsSolver{}
@@ -185,16 +212,93 @@ func Solve(events []Event, results []Result, ratings map[string]float64, timePow
 	return solver.solve(events, results, ratings, timePowerWeighting, options)
 }
 
-func (rs *RatingsSolver) calcError(events []Event, ratings map[string]float64, homeAdvantage, timePowerWeighting float64) float64 {
+// BlendRatings shrinks current ratings toward a prior, returning weight*current +
+// (1-weight)*prior per team; teams present in only one set keep that set's rating
+func BlendRatings(prior, current map[string]float64, weight float64) map[string]float64 {
+	blended := make(map[string]float64)
+	
+	for name, priorRating := range prior {
+		if currentRating, exists := current[name]; exists {
+			blended[name] = weight*currentRating + (1-weight)*priorRating
+		} else {
+			blended[name] = priorRating
+		}
+	}
+	
+	for name, currentRating := range current {
+		if _, exists := prior[name]; !exists {
+			blended[name] = currentRating
+		}
+	}
+	
+	return blended
+}
+
+// WinsorizeRatings pulls ratings more than k standard deviations from the mean back to
+// that boundary, leaving the rest unchanged; k <= 0 returns the ratings as-is
+func WinsorizeRatings(ratings map[string]float64, k float64) map[string]float64 {
+	winsorized := make(map[string]float64, len(ratings))
+	values := make([]float64, 0, len(ratings))
+	for name, rating := range ratings {
+		winsorized[name] = rating
+		values = append(values, rating)
+	}
+	if k <= 0 {
+		return winsorized
+	}
+	
+	mean, std := Mean(values), StdDev(values)
+	lower, upper := mean-k*std, mean+k*std
+	for name, rating := range winsorized {
+		winsorized[name] = math.Max(lower, math.Min(upper, rating))
+	}
+	return winsorized
+}
+
+// NormalizeRatings shifts every rating by the same offset so their mean equals target,
+// returning the shifted ratings and the offset added. Ratings are expected goals, so
+// the shifted set is for comparing runs and leagues, not for pricing
+func NormalizeRatings(ratings map[string]float64, target float64) (map[string]float64, float64) {
+	values := make([]float64, 0, len(ratings))
+	for _, rating := range ratings {
+		values = append(values, rating)
+	}
+	offset := target - Mean(values)
+	
+	normalized := make(map[string]float64, len(ratings))
+	for name, rating := range ratings {
+		normalized[name] = rating + offset
+	}
+	return normalized, offset
+}
+
+func (rs *RatingsSolver) calcError(events []Event, ratings map[string]float64, homeModel HomeAdvantageModel, timePowerWeighting float64) float64 {
+	if rs.rho != nil {
+		homeModel.Rho = rs.rho
+	}
 	var totalWeightedError float64
 	var totalWeight float64
 	
 	for i, event := range events {
-		matrix := NewScoreMatrix(event.Name, ratings, homeAdvantage)
+		homeTeam, awayTeam := event.Teams()
+		matrix := homeModel.NewTeamsScoreMatrix(homeTeam, awayTeam, ratings)
 		modelOdds := matrix.MatchOdds()
-		marketProbs := extractMarketProbabilities(event)
+		marketProbs := extractMarketProbabilities(event, rs.marginMethod)
+		outcomeWeights := rs.outcomeWeights
+		
+		// Home/away-only prices carry no draw, so score the model's split of the decisive outcomes
+		if len(marketProbs) == 2 {
+			modelOdds = twoWayOdds(modelOdds)
+			if outcomeWeights != nil {
+				outcomeWeights = []float64{outcomeWeights[0], outcomeWeights[2]}
+			}
+		}
 		
-		error := rmsError(modelOdds, marketProbs)
+		error := weightedRMSError(modelOdds, marketProbs, outcomeWeights)
+		if rs.xgWeight > 0 && event.HomeXG != nil && event.AwayXG != nil {
+			lambdas := []float64{matrix.HomeLambda, matrix.AwayLambda}
+			error += rs.xgWeight * rmsError(lambdas, []float64{*event.HomeXG, *event.AwayXG})
+		}
 		weight := calculateTimePowerWeight(i, len(events), timePowerWeighting)
 		
 		totalWeightedError += error * weight
@@ -204,7 +308,35 @@ func (rs *RatingsSolver) calcError(events []Event, ratings map[string]float64, h
 	if totalWeight == 0 {
 		return 0
 	}
-	return totalWeightedError / totalWeight
+	return totalWeightedError/totalWeight
+}
+
+// priorPenalty returns the weighted RMS deviation of ratings from the prior ratings, over
+// teams present in both. Only the GA objectives add it, so reported errors measure the
+// fit to the odds alone
+func (rs *RatingsSolver) priorPenalty(ratings map[string]float64) float64 {
+	if rs.priorWeight <= 0 {
+		return 0
+	}
+	
+	// Sum in name order so repeated runs agree to the last bit
+	names := make([]string, 0, len(rs.priorRatings))
+	for name := range rs.priorRatings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	
+	var current, prior []float64
+	for _, name := range names {
+		if rating, exists := ratings[name]; exists {
+			current = append(current, rating)
+			prior = append(prior, rs.priorRatings[name])
+		}
+	}
+	if len(current) == 0 {
+		return 0
+	}
+	return rs.priorWeight * rmsError(current, prior)
 }
 
 func (rs *RatingsSolver) optimizeRatings(events []Event, ratings map[string]float64, homeAdvantage, timePowerWeighting float64, options map[string]interface{}) {
@@ -230,12 +362,13 @@ func (rs *RatingsSolver) optimizeRatings(events []Event, ratings map[string]floa
 		for i, name := range teamNames {
 			tempRatings[name] = params[i]
 		}
-		return rs.calcError(events, tempRatings, homeAdvantage, timePowerWeighting)
+		return rs.calcError(events, tempRatings, AdditiveHomeAdvantage(homeAdvantage), timePowerWeighting) + rs.priorPenalty(tempRatings)
 	}
 	
 	// Optimize
-	ga := newGeneticAlgorithm(options)
-	solution, fitness := ga.optimize(objectiveFn, x0, bounds)
+	solution, fitness := rs.runGA(options, teamNames, func(params []float64) HomeAdvantageModel {
+		return AdditiveHomeAdvantage(homeAdvantage)
+	}, objectiveFn, x0, bounds)
 	
 	// Update ratings
 	for i, name := range teamNames {
@@ -274,12 +407,13 @@ func (rs *RatingsSolver) optimizeRatingsAndBias(events []Event, ratings map[stri
 			tempRatings[name] = params[i]
 		}
 		homeAdvantage := params[len(teamNames)]
-		return rs.calcError(events, tempRatings, homeAdvantage, timePowerWeighting)
+		return rs.calcError(events, tempRatings, AdditiveHomeAdvantage(homeAdvantage), timePowerWeighting) + rs.priorPenalty(tempRatings)
 	}
 	
 	// Optimize
-	ga := newGeneticAlgorithm(options)
-	solution, fitness := ga.optimize(objectiveFn, x0, bounds)
+	solution, fitness := rs.runGA(options, teamNames, func(params []float64) HomeAdvantageModel {
+		return AdditiveHomeAdvantage(params[len(teamNames)])
+	}, objectiveFn, x0, bounds)
 	
 	// Update ratings and get home advantage
 	for i, name := range teamNames {
@@ -291,6 +425,196 @@ func (rs *RatingsSolver) optimizeRatingsAndBias(events []Event, ratings map[stri
 	return homeAdvantage
 }
 
+// optimizeRatingsAndSplitBias jointly fits team ratings with separate home and away
+// lambda multipliers, returning the fitted split home advantage model. Scaling every
+// rating up and both multipliers down prices identically, so the fit is rescaled to keep
+// the ratings' mean at the starting ratings' mean, and HomeAdvantage reports the home
+// lambda's lead over the away lambda for two average teams. Traced generations record
+// the unscaled candidates, which price identically
+func (rs *RatingsSolver) optimizeRatingsAndSplitBias(events []Event, ratings map[string]float64, timePowerWeighting float64, options map[string]interface{}) HomeAdvantageModel {
+	log.Printf("Starting joint optimization of %d team ratings and home/away multipliers", len(ratings))
+	
+	teamNames := make([]string, 0, len(ratings))
+	for name := range ratings {
+		teamNames = append(teamNames, name)
+	}
+	sort.Strings(teamNames)
+	
+	// Create initial solution and bounds
+	x0 := make([]float64, len(teamNames)+2)
+	bounds := make([][]float64, len(teamNames)+2)
+	
+	for i, name := range teamNames {
+		x0[i] = ratings[name]
+		bounds[i] = []float64{RatingMin, RatingMax}
+	}
+	
+	// Home and away multiplier parameters, starting from no advantage
+	for _, idx := range []int{len(teamNames), len(teamNames) + 1} {
+		x0[idx] = 1.0
+		bounds[idx] = []float64{HomeMultiplierMin, HomeMultiplierMax}
+	}
+	
+	splitModel := func(params []float64) HomeAdvantageModel {
+		return HomeAdvantageModel{
+			Split:          true,
+			HomeMultiplier: params[len(teamNames)],
+			AwayMultiplier: params[len(teamNames)+1],
+		}
+	}
+	
+	// Objective function
+	objectiveFn := func(params []float64) float64 {
+		tempRatings := make(map[string]float64)
+		for i, name := range teamNames {
+			tempRatings[name] = params[i]
+		}
+		return rs.calcError(events, tempRatings, splitModel(params), timePowerWeighting) + rs.priorPenalty(tempRatings)
+	}
+	
+	// Optimize
+	solution, fitness := rs.runGA(options, teamNames, splitModel, objectiveFn, x0, bounds)
+	
+	// Update ratings and get multipliers
+	for i, name := range teamNames {
+		ratings[name] = solution[i]
+	}
+	homeModel := splitModel(solution)
+	
+	// Pin the ratings' scale to the starting mean, leaving every lambda unchanged
+	targetMean := Mean(x0[:len(teamNames)])
+	scale := targetMean / Mean(solution[:len(teamNames)])
+	for _, name := range teamNames {
+		ratings[name] *= scale
+	}
+	homeModel.HomeMultiplier /= scale
+	homeModel.AwayMultiplier /= scale
+	homeModel.HomeAdvantage = targetMean * (homeModel.HomeMultiplier - homeModel.AwayMultiplier)
+	
+	log.Printf("Joint optimization completed with final error: %.6f, home multiplier: %.6f, away multiplier: %.6f", 
+		fitness, homeModel.HomeMultiplier, homeModel.AwayMultiplier)
+	return homeModel
+}
+
+// runGA minimises objectiveFn from x0 with the genetic algorithm. With restarts above one
+// it runs that many independent GAs in parallel and keeps the lowest-error solution; each
+// restart draws from its own source seeded from the run's, so seeded runs stay
+// reproducible. Only the first restart is traced
+func (rs *RatingsSolver) runGA(options map[string]interface{}, teamNames []string, homeModel func(params []float64) HomeAdvantageModel, objectiveFn func([]float64) float64, x0 []float64, bounds [][]float64) ([]float64, float64) {
+	if rs.restarts <= 1 {
+		ga := newGeneticAlgorithm(options, rs.rng)
+		rs.traceGenerations(ga, teamNames, homeModel)
+		return ga.optimize(objectiveFn, x0, bounds)
+	}
+	
+	solutions := make([][]float64, rs.restarts)
+	fitnesses := make([]float64, rs.restarts)
+	var wg sync.WaitGroup
+	for r := 0; r < rs.restarts; r++ {
+		ga := newGeneticAlgorithm(options, rand.New(rand.NewSource(rs.rng.Int63())))
+		if r == 0 {
+			rs.traceGenerations(ga, teamNames, homeModel)
+		}
+		
+		wg.Add(1)
+		go func(r int, ga *GeneticAlgorithm) {
+			defer wg.Done()
+			solutions[r], fitnesses[r] = ga.optimize(objectiveFn, x0, bounds)
+		}(r, ga)
+	}
+	wg.Wait()
+	
+	best := 0
+	for r, fitness := range fitnesses {
+		log.Printf("Restart %d/%d: error %.6f", r+1, rs.restarts, fitness)
+		if fitness < fitnesses[best] {
+			best = r
+		}
+	}
+	return solutions[best], fitnesses[best]
+}
+
+// traceGenerations records the GA's best candidate after every generation when tracing
+// is on, decoding team ratings from the leading genes and the home model via homeModel
+func (rs *RatingsSolver) traceGenerations(ga *GeneticAlgorithm, teamNames []string, homeModel func(params []float64) HomeAdvantageModel) {
+	if !rs.traceEnabled {
+		return
+	}
+	ga.onGeneration = func(generation int, best []float64, fitness float64) {
+		ratings := make(map[string]float64, len(teamNames))
+		for i, name := range teamNames {
+			ratings[name] = best[i]
+		}
+		rs.trace = append(rs.trace, SolverTraceEntry{
+			Stage:      SolverTraceGeneration,
+			Generation: generation,
+			Error:      fitness,
+			Ratings:    ratings,
+			HomeModel:  homeModel(best),
+		})
+	}
+}
+
+// copyRatings returns a copy of ratings, so trace entries don't alias the solver's map
+func copyRatings(ratings map[string]float64) map[string]float64 {
+	copied := make(map[string]float64, len(ratings))
+	for name, rating := range ratings {
+		copied[name] = rating
+	}
+	return copied
+}
+
+// optimizeRatingsAndHomeMultiplier jointly fits team ratings with a home lambda
+// multiplier, returning the fitted multiplicative home advantage model
+func (rs *RatingsSolver) optimizeRatingsAndHomeMultiplier(events []Event, ratings map[string]float64, timePowerWeighting float64, options map[string]interface{}) HomeAdvantageModel {
+	log.Printf("Starting joint optimization of %d team ratings and home multiplier", len(ratings))
+	
+	teamNames := make([]string, 0, len(ratings))
+	for name := range ratings {
+		teamNames = append(teamNames, name)
+	}
+	sort.Strings(teamNames)
+	
+	// Create initial solution and bounds
+	x0 := make([]float64, len(teamNames)+1)
+	bounds := make([][]float64, len(teamNames)+1)
+	
+	for i, name := range teamNames {
+		x0[i] = ratings[name]
+		bounds[i] = []float64{RatingMin, RatingMax}
+	}
+	
+	// Home multiplier parameter, starting from no advantage
+	x0[len(teamNames)] = 1.0
+	bounds[len(teamNames)] = []float64{HomeMultiplierMin, HomeMultiplierMax}
+	
+	multiplicativeModel := func(params []float64) HomeAdvantageModel {
+		return MultiplicativeHomeAdvantage(params[len(teamNames)])
+	}
+	
+	// Objective function
+	objectiveFn := func(params []float64) float64 {
+		tempRatings := make(map[string]float64)
+		for i, name := range teamNames {
+			tempRatings[name] = params[i]
+		}
+		return rs.calcError(events, tempRatings, multiplicativeModel(params), timePowerWeighting) + rs.priorPenalty(tempRatings)
+	}
+	
+	// Optimize
+	solution, fitness := rs.runGA(options, teamNames, multiplicativeModel, objectiveFn, x0, bounds)
+	
+	// Update ratings and get multiplier
+	for i, name := range teamNames {
+		ratings[name] = solution[i]
+	}
+	homeModel := multiplicativeModel(solution)
+	
+	log.Printf("Joint optimization completed with final error: %.6f, home multiplier: %.6f", 
+		fitness, homeModel.HomeMultiplier)
+	return homeModel
+}
+
 func (rs *RatingsSolver) initializeRatingsFromLeagueTable(teamNames []string, results []Result) map[string]float64 {
 	leagueTable := CalcLeagueTable(teamNames, results, make(map[string]int))
 	
@@ -307,11 +631,19 @@ func (rs *RatingsSolver) initializeRatingsFromLeagueTable(teamNames []string, re
 		log.Printf("No match events found, using random initialization")
 		ratings := make(map[string]float64)
 		for _, name := range teamNames {
-			ratings[name] = RatingMin + rand.Float64()*(RatingMax-RatingMin)
+			ratings[name] = RatingMin + rs.rng.Float64()*(RatingMax-RatingMin)
 		}
 		return ratings
 	}
 	
+	// Order by recency-weighted points per game instead of actual standings if asked
+	if rs.formHalfLife > 0 {
+		formPPG := calcFormPointsPerGame(teamNames, results, rs.formHalfLife)
+		sort.SliceStable(leagueTable, func(i, j int) bool {
+			return formPPG[leagueTable[i].Name] > formPPG[leagueTable[j].Name]
+		})
+	}
+	
 	// Map league position to rating range
 	ratingSpan := RatingMax - RatingMin
 	ratings := make(map[string]float64)
@@ -333,10 +665,145 @@ func (rs *RatingsSolver) initializeRatingsFromLeagueTable(teamNames []string, re
 	return ratings
 }
 
+// calcFormPointsPerGame calculates each team's points per game with its results weighted
+// by recency: a team's latest result has weight 1, halving every halfLife games back
+func calcFormPointsPerGame(teamNames []string, results []Result, halfLife float64) map[string]float64 {
+	sorted := make([]Result, len(results))
+	copy(sorted, results)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date > sorted[j].Date
+	})
+	
+	gamesBack := make(map[string]int)
+	weightedPoints := make(map[string]float64)
+	totalWeight := make(map[string]float64)
+	for _, result := range sorted {
+		if !result.Played() {
+			continue
+		}
+		homeTeam, awayTeam := result.Teams()
+		homePoints, awayPoints := StandardPointsRule(result.Score[0], result.Score[1])
+		for _, side := range []struct {
+			team   string
+			points int
+		}{{homeTeam, homePoints}, {awayTeam, awayPoints}} {
+			weight := math.Pow(0.5, float64(gamesBack[side.team])/halfLife)
+			weightedPoints[side.team] += weight * float64(side.points)
+			totalWeight[side.team] += weight
+			gamesBack[side.team]++
+		}
+	}
+	
+	formPPG := make(map[string]float64, len(teamNames))
+	for _, name := range teamNames {
+		if totalWeight[name] > 0 {
+			formPPG[name] = weightedPoints[name] / totalWeight[name]
+		}
+	}
+	return formPPG
+}
+
+// initializeRatingsFromScores estimates ratings from historical scorelines. Since the
+// home lambda is rating + home advantage and the away lambda is the rating, the Poisson
+// MLE of a team's rating is its mean goals scored once the empirical home advantage is
+// stripped from its home games. Returns nil if no results have scores
+func (rs *RatingsSolver) initializeRatingsFromScores(teamNames []string, results []Result) map[string]float64 {
+	goalsScored := make(map[string]float64)
+	homeGames := make(map[string]int)
+	played := make(map[string]int)
+	totalHomeGoals, totalAwayGoals, nScored := 0, 0, 0
+	
+	for _, result := range results {
+		if !result.Played() {
+			continue
+		}
+		homeTeam, awayTeam := result.Teams()
+		goalsScored[homeTeam] += float64(result.Score[0])
+		goalsScored[awayTeam] += float64(result.Score[1])
+		homeGames[homeTeam]++
+		played[homeTeam]++
+		played[awayTeam]++
+		totalHomeGoals += result.Score[0]
+		totalAwayGoals += result.Score[1]
+		nScored++
+	}
+	
+	if nScored == 0 {
+		return nil
+	}
+	
+	homeAdvantage := math.Max(0, float64(totalHomeGoals-totalAwayGoals)/float64(nScored))
+	meanRating := float64(totalAwayGoals) / float64(nScored)
+	
+	ratings := make(map[string]float64)
+	for _, name := range teamNames {
+		rating := meanRating
+		if played[name] > 0 {
+			rating = (goalsScored[name] - float64(homeGames[name])*homeAdvantage) / float64(played[name])
+		}
+		ratings[name] = math.Max(RatingMin, math.Min(RatingMax, rating))
+	}
+	
+	log.Printf("Initialized ratings from %d scorelines with empirical home advantage %.3f", nScored, homeAdvantage)
+	
+	return ratings
+}
+
 func (rs *RatingsSolver) solve(events []Event, results []Result, ratings map[string]float64, timePowerWeighting float64, options map[string]interface{}) map[string]interface{} {
 	log.Printf("Starting solver with %d events, max_iterations=%d", len(events), options["generations"].(int))
 	
-	// Initialize ratings from league table if events with scores are provided
+	// Draw from a source of this run's own, seeded for a reproducible fit if asked
+	seed := int64(0)
+	if val, exists := options["seed"]; exists {
+		seed = val.(int64)
+	}
+	rs.rng = NewRand(seed)
+	
+	// Margin removal method for converting event prices to target probabilities
+	if method, exists := options["margin_method"]; exists {
+		rs.marginMethod = method.(string)
+	}
+	
+	// Blend observed xG into the objective for events that carry it
+	if weight, exists := options["xg_weight"]; exists {
+		rs.xgWeight = weight.(float64)
+	}
+	
+	// Collect a structured trace of the fit if asked
+	if trace, exists := options["trace_solver"]; exists {
+		rs.traceEnabled = trace.(bool)
+	}
+	
+	// Weight the home, draw and away error terms unequally if asked
+	if weights, exists := options["outcome_weights"]; exists {
+		rs.outcomeWeights = weights.([]float64)
+	}
+	
+	// Anchor ratings to external priors if asked
+	if priors, exists := options["prior_ratings"]; exists {
+		rs.priorRatings = priors.(map[string]float64)
+	}
+	if weight, exists := options["prior_weight"]; exists {
+		rs.priorWeight = weight.(float64)
+	}
+	
+	// Price with a non-default Dixon-Coles rho if asked
+	if rho, exists := options["rho"]; exists {
+		value := rho.(float64)
+		rs.rho = &value
+	}
+	
+	// Run several independent GAs per fit if asked
+	if restarts, exists := options["restarts"]; exists {
+		rs.restarts = restarts.(int)
+	}
+	
+	// Seed from a recency-weighted league table rather than scorelines if asked
+	if halfLife, exists := options["form_half_life"]; exists {
+		rs.formHalfLife = halfLife.(float64)
+	}
+	
+	// Initialize ratings from results (scorelines, else league table) if provided
 	useLeagueTableInit := true
 	if val, exists := options["use_league_table_init"]; exists {
 		useLeagueTableInit = val.(bool)
@@ -352,36 +819,77 @@ func (rs *RatingsSolver) solve(events []Event, results []Result, ratings map[str
 			}
 			sort.Strings(teamNames)
 			
-			leagueTableRatings := rs.initializeRatingsFromLeagueTable(teamNames, results)
-			for name, rating := range leagueTableRatings {
+			// Prefer scoreline estimates, falling back to league position mapping;
+			// form weighting only applies to the league table
+			var initialRatings map[string]float64
+			if rs.formHalfLife <= 0 {
+				initialRatings = rs.initializeRatingsFromScores(teamNames, results)
+			}
+			if initialRatings == nil {
+				initialRatings = rs.initializeRatingsFromLeagueTable(teamNames, results)
+			}
+			for name, rating := range initialRatings {
 				ratings[name] = rating
 			}
 		}
 	}
 	
-	var homeAdvantage float64
+	if rs.traceEnabled {
+		rs.trace = append(rs.trace, SolverTraceEntry{
+			Stage:   SolverTraceInitial,
+			Ratings: copyRatings(ratings),
+		})
+	}
+	
+	var homeModel HomeAdvantageModel
+	
+	splitHomeAdvantage := false
+	if val, exists := options["split_home_advantage"]; exists {
+		splitHomeAdvantage = val.(bool)
+	}
+	multiplicativeHomeAdvantage := false
+	if val, exists := options["multiplicative_home_advantage"]; exists {
+		multiplicativeHomeAdvantage = val.(bool)
+	}
 	
-	// Check if home advantage is provided
-	if ha, exists := options["home_advantage"]; exists {
-		homeAdvantage = ha.(float64)
-		rs.optimizeRatings(events, ratings, homeAdvantage, timePowerWeighting, options)
+	// Check if home advantage is split, multiplicative or provided
+	if splitHomeAdvantage {
+		homeModel = rs.optimizeRatingsAndSplitBias(events, ratings, timePowerWeighting, options)
+	} else if multiplicativeHomeAdvantage {
+		homeModel = rs.optimizeRatingsAndHomeMultiplier(events, ratings, timePowerWeighting, options)
+	} else if ha, exists := options["home_advantage"]; exists {
+		homeModel = AdditiveHomeAdvantage(ha.(float64))
+		rs.optimizeRatings(events, ratings, homeModel.HomeAdvantage, timePowerWeighting, options)
 	} else {
-		homeAdvantage = rs.optimizeRatingsAndBias(events, ratings, timePowerWeighting, options)
+		homeModel = AdditiveHomeAdvantage(rs.optimizeRatingsAndBias(events, ratings, timePowerWeighting, options))
 	}
+	homeModel.Rho = rs.rho
 	
-	error := rs.calcError(events, ratings, homeAdvantage, timePowerWeighting)
+	error := rs.calcError(events, ratings, homeModel, timePowerWeighting)
 	log.Printf("Solver completed with final error: %.6f", error)
 	
+	if rs.traceEnabled {
+		rs.trace = append(rs.trace, SolverTraceEntry{
+			Stage:     SolverTraceFinal,
+			Error:     error,
+			Ratings:   copyRatings(ratings),
+			HomeModel: homeModel,
+		})
+	}
+	
 	return map[string]interface{}{
 		"ratings":        ratings,
-		"home_advantage": homeAdvantage,
+		"home_advantage": homeModel.HomeAdvantage,
+		"home_model":     homeModel,
 		"error":          error,
+		"trace":          rs.trace,
 	}
 }
 
 // extractMarketProbabilities converts event match odds to normalized probabilities
-func extractMarketProbabilities(event Event) []float64 {
-	probs, err := NormalizeProbabilities(event.MatchOdds.Prices)
+// using the given margin removal method
+func extractMarketProbabilities(event Event, marginMethod string) []float64 {
+	probs, err := NormalizeProbabilitiesWithMethod(event.MatchOdds.Prices, marginMethod)
 	if err != nil {
 		// Return zero probabilities on error (should not happen with valid data)
 		return make([]float64, len(event.MatchOdds.Prices))
@@ -389,6 +897,16 @@ func extractMarketProbabilities(event Event) []float64 {
 	return probs
 }
 
+// twoWayOdds converts [home, draw, away] probabilities to home and away probabilities
+// conditional on a decisive result, matching a 2-price home/away market
+func twoWayOdds(odds []float64) []float64 {
+	decisive := odds[0] + odds[2]
+	if decisive == 0 {
+		return []float64{0.5, 0.5}
+	}
+	return []float64{odds[0] / decisive, odds[2] / decisive}
+}
+
 // calculateTimePowerWeight calculates time power weighting for events
 // Most recent event gets weight 1.0, oldest gets weight 0.0
 // Power controls the decay curve: 1.0 = linear, >1 = faster decay, <1 = slower decay
@@ -421,3 +939,26 @@ func rmsError(x, y []float64) float64 {
 	
 	return math.Sqrt(sum / float64(len(x)))
 }
+
+// weightedRMSError is rmsError with each squared difference scaled by the matching
+// weight, normalised by the weight total; nil weights fall back to rmsError
+func weightedRMSError(x, y, weights []float64) float64 {
+	if weights == nil {
+		return rmsError(x, y)
+	}
+	if len(x) != len(y) || len(x) != len(weights) {
+		return math.Inf(1)
+	}
+	
+	sum, totalWeight := 0.0, 0.0
+	for i := range x {
+		diff := x[i] - y[i]
+		sum += weights[i] * diff * diff
+		totalWeight += weights[i]
+	}
+	if totalWeight == 0 {
+		return math.Inf(1)
+	}
+	
+	return math.Sqrt(sum / totalWeight)
+}
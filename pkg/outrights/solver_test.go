@@ -0,0 +1,466 @@
+package outrights
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// testGAOptions is a small GA configuration for quick solver tests
+func testGAOptions(generations int) map[string]interface{} {
+	return map[string]interface{}{
+		"generations":          generations,
+		"population_size":      20,
+		"mutation_factor":      0.1,
+		"elite_ratio":          0.1,
+		"init_std":             0.2,
+		"log_interval":         1000,
+		"decay_exponent":       0.5,
+		"mutation_probability": 0.1,
+		"debug":                false,
+	}
+}
+
+// rastrigin is a rugged objective with many local minima, where restarts pay off
+func rastrigin(x []float64) float64 {
+	total := 10 * float64(len(x))
+	for _, xi := range x {
+		total += xi*xi - 10*math.Cos(2*math.Pi*xi)
+	}
+	return total
+}
+
+func TestRunGARestartsKeepBest(t *testing.T) {
+	options := testGAOptions(30)
+	x0 := []float64{3, -3, 2.5, -2.5}
+	bounds := [][]float64{{-5.12, 5.12}, {-5.12, 5.12}, {-5.12, 5.12}, {-5.12, 5.12}}
+	noHome := func([]float64) HomeAdvantageModel { return HomeAdvantageModel{} }
+
+	for _, restarts := range []int{2, 4} {
+		rs := NewRatingsSolver()
+		rs.restarts = restarts
+		rs.rng = rand.New(rand.NewSource(1))
+		_, best := rs.runGA(options, nil, noHome, rastrigin, x0, bounds)
+
+		// Replay each restart alone from the seed runGA derives for it
+		seeds := rand.New(rand.NewSource(1))
+		lowest := math.Inf(1)
+		for r := 0; r < restarts; r++ {
+			ga := newGeneticAlgorithm(options, rand.New(rand.NewSource(seeds.Int63())))
+			_, fitness := ga.optimize(rastrigin, x0, bounds)
+			if best > fitness {
+				t.Errorf("restarts=%d: best %.6f exceeds restart %d's %.6f", restarts, best, r, fitness)
+			}
+			lowest = math.Min(lowest, fitness)
+		}
+		if best != lowest {
+			t.Errorf("restarts=%d: best %.6f, want the lowest restart %.6f", restarts, best, lowest)
+		}
+	}
+}
+
+func TestNormalizeRatings(t *testing.T) {
+	ratings := map[string]float64{"A": 0.4, "B": 1.1, "C": 2.5}
+	for _, target := range []float64{NormalizedRatingMean, 0, 3} {
+		normalized, offset := NormalizeRatings(ratings, target)
+		values := make([]float64, 0, len(normalized))
+		for name, rating := range normalized {
+			values = append(values, rating)
+			if math.Abs(rating-offset-ratings[name]) > 1e-12 {
+				t.Errorf("target %g: %s shifted by %g, want %g", target, name, rating-ratings[name], offset)
+			}
+		}
+		if mean := Mean(values); math.Abs(mean-target) > 1e-12 {
+			t.Errorf("target %g: normalized mean %g", target, mean)
+		}
+	}
+}
+
+// fairEvents prices every pairing of ratings home and away under homeModel, with no margin
+func fairEvents(ratings map[string]float64, homeModel HomeAdvantageModel) []Event {
+	names := make([]string, 0, len(ratings))
+	for name := range ratings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var events []Event
+	for _, home := range names {
+		for _, away := range names {
+			if home == away {
+				continue
+			}
+			odds := homeModel.NewTeamsScoreMatrix(home, away, ratings).MatchOdds()
+			events = append(events, Event{
+				Name:      home + " vs " + away,
+				Date:      "2024-01-01",
+				MatchOdds: MatchOdds{Prices: []float64{1 / odds[0], 1 / odds[1], 1 / odds[2]}},
+			})
+		}
+	}
+	return events
+}
+
+func TestSplitHomeAdvantageRecoversMultipliers(t *testing.T) {
+	truth := map[string]float64{"A": 0.9, "B": 1.1, "C": 1.25, "D": 1.35, "E": 1.5, "F": 1.7}
+	trueModel := HomeAdvantageModel{Split: true, HomeMultiplier: 1.15, AwayMultiplier: 0.9}
+	events := fairEvents(truth, trueModel)
+
+	// Start at the true mean, which the fit keeps
+	ratings := make(map[string]float64)
+	for name := range truth {
+		ratings[name] = 1.3
+	}
+	options := testGAOptions(150)
+	options["population_size"] = 30
+	options["use_league_table_init"] = false
+	options["split_home_advantage"] = true
+	options["seed"] = int64(1)
+	resp := Solve(events, nil, ratings, 0, options)
+
+	homeModel := resp["home_model"].(HomeAdvantageModel)
+	if math.Abs(homeModel.HomeMultiplier-trueModel.HomeMultiplier) > 0.05 {
+		t.Errorf("home multiplier %.4f, want %.4f", homeModel.HomeMultiplier, trueModel.HomeMultiplier)
+	}
+	if math.Abs(homeModel.AwayMultiplier-trueModel.AwayMultiplier) > 0.05 {
+		t.Errorf("away multiplier %.4f, want %.4f", homeModel.AwayMultiplier, trueModel.AwayMultiplier)
+	}
+	wantAdvantage := 1.3 * (trueModel.HomeMultiplier - trueModel.AwayMultiplier)
+	if got := resp["home_advantage"].(float64); math.Abs(got-wantAdvantage) > 0.05 {
+		t.Errorf("home advantage %.4f, want %.4f", got, wantAdvantage)
+	}
+
+	solved := resp["ratings"].(map[string]float64)
+	values := make([]float64, 0, len(solved))
+	for _, rating := range solved {
+		values = append(values, rating)
+	}
+	if mean := Mean(values); math.Abs(mean-1.3) > 1e-9 {
+		t.Errorf("ratings mean %.6f, want the starting mean 1.3", mean)
+	}
+}
+
+func TestPriorPenaltyLeftOutOfReportedError(t *testing.T) {
+	truth := map[string]float64{"A": 1.0, "B": 1.3, "C": 1.6}
+	events := fairEvents(truth, AdditiveHomeAdvantage(0.3))
+	priors := map[string]float64{"A": 2.0, "B": 2.0, "C": 2.0}
+
+	ratings := map[string]float64{"A": 1.3, "B": 1.3, "C": 1.3}
+	options := testGAOptions(50)
+	options["use_league_table_init"] = false
+	options["prior_ratings"] = priors
+	options["prior_weight"] = 1.0
+	options["seed"] = int64(1)
+	resp := Solve(events, nil, ratings, 0, options)
+
+	solved := resp["ratings"].(map[string]float64)
+	homeModel := resp["home_model"].(HomeAdvantageModel)
+	oddsError := NewRatingsSolver().calcError(events, solved, homeModel, 0)
+	if got := resp["error"].(float64); math.Abs(got-oddsError) > 1e-12 {
+		t.Errorf("reported error %.6f, want the odds-only error %.6f", got, oddsError)
+	}
+
+	penalised := &RatingsSolver{priorRatings: priors, priorWeight: 1.0}
+	if penalised.calcError(events, solved, homeModel, 0) != oddsError {
+		t.Error("calcError includes the prior penalty")
+	}
+	if penalised.priorPenalty(solved) <= 0 {
+		t.Error("expected a positive penalty for ratings away from the priors")
+	}
+}
+
+// sampledResults plays every pairing of ratings home and away rounds times under
+// homeModel, drawing scores from rng
+func sampledResults(ratings map[string]float64, homeModel HomeAdvantageModel, rounds int, rng *rand.Rand) []Result {
+	var results []Result
+	for round := 0; round < rounds; round++ {
+		for _, event := range fairEvents(ratings, homeModel) {
+			home, away := event.Teams()
+			homeLambda, awayLambda := homeModel.Lambdas(ratings[home], ratings[away])
+			homeGoals, awayGoals := sampleScore(homeLambda, awayLambda, homeModel.dixonColesRho(), DefaultN, rng)
+			results = append(results, Result{Name: event.Name, Date: "2024-01-01", Score: []int{homeGoals, awayGoals}})
+		}
+	}
+	return results
+}
+
+func TestScorelineInitBeatsLeagueTableInit(t *testing.T) {
+	truth := map[string]float64{"A": 0.8, "B": 1.0, "C": 1.2, "D": 1.4, "E": 1.6, "F": 1.9}
+	homeModel := AdditiveHomeAdvantage(0.3)
+	events := fairEvents(truth, homeModel)
+	results := sampledResults(truth, homeModel, 4, rand.New(rand.NewSource(1)))
+	names := []string{"A", "B", "C", "D", "E", "F"}
+
+	rs := &RatingsSolver{rng: rand.New(rand.NewSource(1))}
+	scoreError := rs.calcError(events, rs.initializeRatingsFromScores(names, results), homeModel, 0)
+	tableError := rs.calcError(events, rs.initializeRatingsFromLeagueTable(names, results), homeModel, 0)
+	if scoreError >= tableError {
+		t.Errorf("scoreline init error %.4f not below league table init error %.4f", scoreError, tableError)
+	}
+}
+
+func TestTwoPriceEvents(t *testing.T) {
+	truth := map[string]float64{"A": 1.0, "B": 1.3, "C": 1.6}
+	homeModel := AdditiveHomeAdvantage(0.3)
+	events := fairEvents(truth, homeModel)
+	for i, event := range events {
+		prices := event.MatchOdds.Prices
+		probs := twoWayOdds([]float64{1 / prices[0], 1 / prices[1], 1 / prices[2]})
+		events[i].MatchOdds.Prices = []float64{1 / probs[0], 1 / probs[1]}
+	}
+	perturbed := map[string]float64{"A": 1.2, "B": 1.1, "C": 1.6}
+
+	for _, weights := range [][]float64{nil, {2, 100, 1}} {
+		rs := &RatingsSolver{outcomeWeights: weights}
+		if got := rs.calcError(events, truth, homeModel, 0); got > 1e-9 {
+			t.Errorf("weights %v: error %g at the true ratings, want 0", weights, got)
+		}
+
+		// Only the home and away terms are scored, the draw weight dropping out
+		want := 0.0
+		for _, event := range events {
+			home, away := event.Teams()
+			model := twoWayOdds(homeModel.NewTeamsScoreMatrix(home, away, perturbed).MatchOdds())
+			market := extractMarketProbabilities(event, "")
+			if weights == nil {
+				want += rmsError(model, market)
+			} else {
+				want += weightedRMSError(model, market, []float64{weights[0], weights[2]})
+			}
+		}
+		want /= float64(len(events))
+		if got := rs.calcError(events, perturbed, homeModel, 0); math.Abs(got-want) > 1e-12 {
+			t.Errorf("weights %v: error %g, want %g from the home and away terms", weights, got, want)
+		}
+	}
+
+	// The solver fits 2-price books end to end
+	ratings := map[string]float64{"A": 1.3, "B": 1.3, "C": 1.3}
+	options := testGAOptions(100)
+	options["use_league_table_init"] = false
+	options["home_advantage"] = 0.3
+	options["seed"] = int64(1)
+	resp := Solve(events, nil, ratings, 0, options)
+	if got := resp["error"].(float64); got > 0.01 {
+		t.Errorf("solved 2-price error %g, want under 0.01", got)
+	}
+}
+
+func TestFormWeightingFavoursHotStreak(t *testing.T) {
+	// A loses its first four games and wins its last two; the others draw among themselves
+	results := []Result{
+		{Name: "A vs B", Date: "2024-08-01", Score: []int{0, 1}},
+		{Name: "C vs A", Date: "2024-08-08", Score: []int{1, 0}},
+		{Name: "A vs D", Date: "2024-08-15", Score: []int{0, 1}},
+		{Name: "B vs A", Date: "2024-08-22", Score: []int{1, 0}},
+		{Name: "B vs C", Date: "2024-08-29", Score: []int{1, 1}},
+		{Name: "C vs D", Date: "2024-09-05", Score: []int{1, 1}},
+		{Name: "D vs B", Date: "2024-09-12", Score: []int{1, 1}},
+		{Name: "A vs C", Date: "2024-09-19", Score: []int{2, 0}},
+		{Name: "D vs A", Date: "2024-09-26", Score: []int{0, 2}},
+	}
+	names := []string{"A", "B", "C", "D"}
+
+	rs := &RatingsSolver{rng: rand.New(rand.NewSource(1))}
+	table := rs.initializeRatingsFromLeagueTable(names, results)
+	rs.formHalfLife = 1
+	form := rs.initializeRatingsFromLeagueTable(names, results)
+	if form["A"] <= table["A"] {
+		t.Errorf("A's form rating %.3f not above its unweighted rating %.3f", form["A"], table["A"])
+	}
+	if form["A"] != RatingMax {
+		t.Errorf("A's form rating %.3f, want the top rating %.1f", form["A"], RatingMax)
+	}
+}
+
+func TestWinsorizeRatings(t *testing.T) {
+	ratings := map[string]float64{"A": 1.0, "B": 1.1, "C": 1.2, "D": 1.3, "E": 1.4, "F": 1.5, "G": 1.6, "H": 1.7, "I": 1.8, "J": 5.5}
+	winsorized := WinsorizeRatings(ratings, 2)
+
+	values := make([]float64, 0, len(ratings))
+	for _, rating := range ratings {
+		values = append(values, rating)
+	}
+	upper := Mean(values) + 2*StdDev(values)
+	if math.Abs(winsorized["J"]-upper) > 1e-12 || winsorized["J"] >= ratings["J"] {
+		t.Errorf("J winsorized to %.4f, want the upper bound %.4f", winsorized["J"], upper)
+	}
+	for name, rating := range ratings {
+		if name != "J" && winsorized[name] != rating {
+			t.Errorf("%s moved from %g to %g", name, rating, winsorized[name])
+		}
+	}
+	if ratings["J"] != 5.5 {
+		t.Error("WinsorizeRatings modified its input")
+	}
+
+	for _, k := range []float64{0, -1} {
+		if got := WinsorizeRatings(ratings, k); !reflect.DeepEqual(got, ratings) {
+			t.Errorf("k=%g: ratings changed to %v", k, got)
+		}
+	}
+}
+
+func TestMultiplicativeHomeAdvantageRecoversMultiplier(t *testing.T) {
+	truth := map[string]float64{"A": 0.9, "B": 1.1, "C": 1.25, "D": 1.35, "E": 1.5, "F": 1.7}
+	trueModel := MultiplicativeHomeAdvantage(1.25)
+	events := fairEvents(truth, trueModel)
+
+	ratings := make(map[string]float64)
+	for name := range truth {
+		ratings[name] = 1.3
+	}
+	options := testGAOptions(150)
+	options["population_size"] = 30
+	options["use_league_table_init"] = false
+	options["multiplicative_home_advantage"] = true
+	options["seed"] = int64(1)
+	resp := Solve(events, nil, ratings, 0, options)
+
+	homeModel := resp["home_model"].(HomeAdvantageModel)
+	if !homeModel.Multiplicative {
+		t.Fatalf("home model %+v isn't multiplicative", homeModel)
+	}
+	if math.Abs(homeModel.HomeMultiplier-trueModel.HomeMultiplier) > 0.05 {
+		t.Errorf("home multiplier %.4f, want %.4f", homeModel.HomeMultiplier, trueModel.HomeMultiplier)
+	}
+	solved := resp["ratings"].(map[string]float64)
+	for name, rating := range truth {
+		if math.Abs(solved[name]-rating) > 0.05 {
+			t.Errorf("%s rating %.4f, want %.4f", name, solved[name], rating)
+		}
+	}
+}
+
+func TestBlendRatings(t *testing.T) {
+	prior := map[string]float64{"A": 1, "B": 2, "P": 3}
+	current := map[string]float64{"A": 2, "B": 1, "C": 4}
+	tests := []struct {
+		name   string
+		weight float64
+		want   map[string]float64
+	}{
+		{"prior only", 0, map[string]float64{"A": 1, "B": 2, "P": 3, "C": 4}},
+		{"current only", 1, map[string]float64{"A": 2, "B": 1, "P": 3, "C": 4}},
+		{"even blend", 0.5, map[string]float64{"A": 1.5, "B": 1.5, "P": 3, "C": 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BlendRatings(prior, current, tt.weight); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BlendRatings = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got := BlendRatings(nil, current, 0.5); !reflect.DeepEqual(got, current) {
+		t.Errorf("blending with no prior = %v, want the current ratings", got)
+	}
+}
+
+func TestSolverTrace(t *testing.T) {
+	truth := map[string]float64{"A": 1.0, "B": 1.2, "C": 1.4, "D": 1.6}
+	events := fairEvents(truth, AdditiveHomeAdvantage(0.3))
+	solve := func(trace bool) map[string]interface{} {
+		ratings := map[string]float64{"A": 1.3, "B": 1.3, "C": 1.3, "D": 1.3}
+		options := testGAOptions(10)
+		options["use_league_table_init"] = false
+		options["seed"] = int64(1)
+		options["trace_solver"] = trace
+		return Solve(events, nil, ratings, 0, options)
+	}
+
+	if trace := solve(false)["trace"].([]SolverTraceEntry); len(trace) != 0 {
+		t.Errorf("expected no trace without trace_solver, got %d entries", len(trace))
+	}
+
+	resp := solve(true)
+	trace := resp["trace"].([]SolverTraceEntry)
+	if len(trace) != 12 {
+		t.Fatalf("expected an initial, 10 generation and a final entry, got %d", len(trace))
+	}
+	if trace[0].Stage != SolverTraceInitial || trace[0].Ratings["A"] != 1.3 {
+		t.Errorf("first entry %+v, want the initial ratings", trace[0])
+	}
+	for i, entry := range trace[1:11] {
+		if entry.Stage != SolverTraceGeneration || entry.Generation != i+1 {
+			t.Errorf("entry %d: stage %s generation %d, want generation %d", i+1, entry.Stage, entry.Generation, i+1)
+		}
+		if i > 0 && entry.Error > trace[i].Error {
+			t.Errorf("generation %d error %.6f rose from %.6f", entry.Generation, entry.Error, trace[i].Error)
+		}
+		if len(entry.Ratings) != len(truth) {
+			t.Errorf("generation %d traces %d ratings, want %d", entry.Generation, len(entry.Ratings), len(truth))
+		}
+	}
+	final := trace[11]
+	if final.Stage != SolverTraceFinal || final.Error != resp["error"].(float64) {
+		t.Errorf("last entry %+v, want the final fit with error %.6f", final, resp["error"])
+	}
+	if !reflect.DeepEqual(final.Ratings, resp["ratings"]) {
+		t.Errorf("final traced ratings %v differ from the solution %v", final.Ratings, resp["ratings"])
+	}
+}
+
+func TestWeightedRMSError(t *testing.T) {
+	tests := []struct {
+		name    string
+		x, y    []float64
+		weights []float64
+		want    float64
+	}{
+		{"nil weights", []float64{0.5, 0.3, 0.2}, []float64{0.4, 0.3, 0.3}, nil, rmsError([]float64{0.5, 0.3, 0.2}, []float64{0.4, 0.3, 0.3})},
+		{"equal weights", []float64{0.5, 0.3, 0.2}, []float64{0.4, 0.3, 0.3}, []float64{2, 2, 2}, rmsError([]float64{0.5, 0.3, 0.2}, []float64{0.4, 0.3, 0.3})},
+		{"uneven weights", []float64{1, 2}, []float64{0, 0}, []float64{3, 1}, math.Sqrt(7.0 / 4)},
+		{"single outcome", []float64{0.5, 0.3, 0.2}, []float64{0.25, 0.3, 0.45}, []float64{1, 0, 0}, 0.25},
+		{"length mismatch", []float64{0.5, 0.5}, []float64{0.5, 0.5}, []float64{1, 1, 1}, math.Inf(1)},
+		{"zero total weight", []float64{0.5, 0.5}, []float64{0.4, 0.6}, []float64{0, 0}, math.Inf(1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := weightedRMSError(tt.x, tt.y, tt.weights)
+			if math.IsInf(tt.want, 1) {
+				if !math.IsInf(got, 1) {
+					t.Errorf("weightedRMSError = %g, want +Inf", got)
+				}
+				return
+			}
+			if math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("weightedRMSError = %g, want %g", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestXGErrorTerm(t *testing.T) {
+	truth := map[string]float64{"A": 1.0, "B": 1.3, "C": 1.6}
+	homeModel := AdditiveHomeAdvantage(0.3)
+	events := fairEvents(truth, homeModel)
+
+	// Observed xG misses each lambda by 0.3, an RMS miss of 0.3; the last event has no xG
+	for i := range events[:len(events)-1] {
+		home, away := events[i].Teams()
+		homeLambda, awayLambda := homeModel.Lambdas(truth[home], truth[away])
+		homeXG, awayXG := homeLambda+0.3, awayLambda-0.3
+		events[i].HomeXG, events[i].AwayXG = &homeXG, &awayXG
+	}
+
+	rs := &RatingsSolver{}
+	base := rs.calcError(events, truth, homeModel, 0)
+	rs.xgWeight = 2
+	withXG := rs.calcError(events, truth, homeModel, 0)
+	n := float64(len(events))
+	if want := base + 2*0.3*(n-1)/n; math.Abs(withXG-want) > 1e-9 {
+		t.Errorf("error with xG %.6f, want %.6f", withXG, want)
+	}
+
+	// Matching xG adds nothing
+	for i := range events[:len(events)-1] {
+		home, away := events[i].Teams()
+		homeLambda, awayLambda := homeModel.Lambdas(truth[home], truth[away])
+		events[i].HomeXG, events[i].AwayXG = &homeLambda, &awayLambda
+	}
+	if got := rs.calcError(events, truth, homeModel, 0); math.Abs(got-base) > 1e-12 {
+		t.Errorf("error with exact xG %.6f, want %.6f", got, base)
+	}
+}
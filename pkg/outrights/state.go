@@ -1,10 +1,21 @@
 package outrights
 
 import (
+	"math"
 	"sort"
 )
 
 func CalcLeagueTable(teamNames []string, results []Result, handicaps map[string]int) []Team {
+	return CalcLeagueTableWithTieBreakers(teamNames, results, handicaps, nil)
+}
+
+// CalcLeagueTableWithTieBreakers builds the current table, ranking teams level on points
+// by each tie-break key in order (see ValidateTieBreakers); nil means goal difference
+func CalcLeagueTableWithTieBreakers(teamNames []string, results []Result, handicaps map[string]int, tieBreakers []string) []Team {
+	if len(tieBreakers) == 0 {
+		tieBreakers = []string{TieBreakGoalDifference}
+	}
+	
 	teams := make(map[string]*Team)
 	
 	// Initialize teams
@@ -26,10 +37,10 @@ func CalcLeagueTable(teamNames []string, results []Result, handicaps map[string]
 	
 	// Process results
 	for _, result := range results {
-		homeTeam, awayTeam := ParseEventName(result.Name)
+		homeTeam, awayTeam := result.Teams()
 		
-		// Skip if we don't have match result data
-		if len(result.Score) != 2 {
+		// Skip if we don't have match result data, or the game was voided
+		if !result.Played() {
 			continue
 		}
 		
@@ -48,18 +59,22 @@ func CalcLeagueTable(teamNames []string, results []Result, handicaps map[string]
 		if homeGoals > awayGoals {
 			// Home team wins
 			teams[homeTeam].Points += 3
+			teams[homeTeam].Wins += 1
 		} else if homeGoals < awayGoals {
 			// Away team wins
 			teams[awayTeam].Points += 3
+			teams[awayTeam].Wins += 1
 		} else {
 			// Draw
 			teams[homeTeam].Points += 1
 			teams[awayTeam].Points += 1
 		}
 		
-		// Update goal difference and games played
+		// Update goal difference, goals scored and games played
 		teams[homeTeam].GoalDifference += homeGoals - awayGoals
 		teams[awayTeam].GoalDifference += awayGoals - homeGoals
+		teams[homeTeam].GoalsFor += homeGoals
+		teams[awayTeam].GoalsFor += awayGoals
 		teams[homeTeam].Played += 1
 		teams[awayTeam].Played += 1
 	}
@@ -70,25 +85,66 @@ func CalcLeagueTable(teamNames []string, results []Result, handicaps map[string]
 		result = append(result, *team)
 	}
 	
-	// Sort by points (descending), then by goal difference (descending)
+	// Sort by points (descending), then by each tie-break key (descending)
 	sort.Slice(result, func(i, j int) bool {
-		if result[i].Points == result[j].Points {
-			return result[i].GoalDifference > result[j].GoalDifference
-		}
-		return result[i].Points > result[j].Points
+		return rankAbove(teamTableRow(result[i]), teamTableRow(result[j]), tieBreakers)
 	})
 	
 	return result
 }
 
+// rankAbove reports whether row a finishes above row b: on points, then on each
+// tie-break key in order. Keys are compared strictly lexically rather than folded into
+// a weighted score, so no goal difference can overturn a points gap. The current table
+// and every simulated ranking share it
+func rankAbove(a, b TableRow, tieBreakers []string) bool {
+	if a.Points != b.Points {
+		return a.Points > b.Points
+	}
+	for _, key := range tieBreakers {
+		var x, y int
+		switch key {
+		case TieBreakGoalDifference:
+			x, y = a.GoalDifference, b.GoalDifference
+		case TieBreakGoalsFor:
+			x, y = a.GoalsFor, b.GoalsFor
+		case TieBreakWins:
+			x, y = a.Wins, b.Wins
+		}
+		if x != y {
+			return x > y
+		}
+	}
+	return false
+}
+
+// teamTableRow returns team's ranking fields as a table row
+func teamTableRow(team Team) TableRow {
+	return TableRow{
+		Team:           team.Name,
+		Points:         team.Points,
+		GoalDifference: team.GoalDifference,
+		GoalsFor:       team.GoalsFor,
+		Wins:           team.Wins,
+	}
+}
+
 func CalcRemainingFixtures(teamNames []string, results []Result, rounds int) []string {
+	return CalcRemainingFixturesWithRounds(teamNames, results, rounds, nil)
+}
+
+// CalcRemainingFixturesWithRounds is CalcRemainingFixtures for unbalanced schedules, where
+// pairingRounds maps a fixture name ("Home vs Away") to the number of times it is played;
+// pairings absent from the map are played rounds times
+func CalcRemainingFixturesWithRounds(teamNames []string, results []Result, rounds int, pairingRounds map[string]int) []string {
 	// Count how many times each fixture has been played
 	playedCounts := make(map[string]int)
 	
 	// Count already played fixtures
 	for _, result := range results {
-		if len(result.Score) == 2 {
-			playedCounts[result.Name]++
+		if result.Played() {
+			homeTeam, awayTeam := result.Teams()
+			playedCounts[homeTeam+" vs "+awayTeam]++
 		}
 	}
 	
@@ -101,8 +157,13 @@ func CalcRemainingFixtures(teamNames []string, results []Result, rounds int) []s
 				fixtureName := homeTeam + " vs " + awayTeam
 				playedCount := playedCounts[fixtureName]
 				
+				fixtureRounds := rounds
+				if n, exists := pairingRounds[fixtureName]; exists {
+					fixtureRounds = n
+				}
+				
 				// Add remaining fixtures for this matchup
-				for k := playedCount; k < rounds; k++ {
+				for k := playedCount; k < fixtureRounds; k++ {
 					remainingFixtures = append(remainingFixtures, fixtureName)
 				}
 			}
@@ -112,4 +173,357 @@ func CalcRemainingFixtures(teamNames []string, results []Result, rounds int) []s
 	return remainingFixtures
 }
 
+// CalcRemainingScheduleStrength calculates the average rating of each team's opponents
+// across its remaining fixtures; teams with nothing left to play get 0
+func CalcRemainingScheduleStrength(remainingFixtures []string, ratings map[string]float64) map[string]float64 {
+	totals := make(map[string]float64)
+	counts := make(map[string]int)
+	
+	for _, fixture := range remainingFixtures {
+		homeTeam, awayTeam := ParseEventName(fixture)
+		totals[homeTeam] += ratings[awayTeam]
+		counts[homeTeam]++
+		totals[awayTeam] += ratings[homeTeam]
+		counts[awayTeam]++
+	}
+	
+	strength := make(map[string]float64)
+	for name := range ratings {
+		if counts[name] > 0 {
+			strength[name] = totals[name] / float64(counts[name])
+		} else {
+			strength[name] = 0.0
+		}
+	}
+	
+	return strength
+}
 
+// CalcDeterministicSeasonPoints calculates each team's current points plus the expected
+// points from its remaining fixtures, without simulation
+func CalcDeterministicSeasonPoints(leagueTable []Team, remainingFixtures []string, ratings map[string]float64, homeModel HomeAdvantageModel) map[string]float64 {
+	seasonPoints := make(map[string]float64)
+	for name, breakdown := range CalcExpectedPointsBreakdown(leagueTable, remainingFixtures, ratings, homeModel) {
+		seasonPoints[name] = breakdown.Total
+	}
+	return seasonPoints
+}
+
+// CalcExpectedPointsBreakdown splits each team's deterministic expected season points
+// into current points plus the expected points from each of its remaining fixtures
+func CalcExpectedPointsBreakdown(leagueTable []Team, remainingFixtures []string, ratings map[string]float64, homeModel HomeAdvantageModel) map[string]*ExpectedPointsBreakdown {
+	breakdowns := make(map[string]*ExpectedPointsBreakdown)
+	for _, team := range leagueTable {
+		breakdowns[team.Name] = &ExpectedPointsBreakdown{
+			CurrentPoints: team.Points,
+			Fixtures:      []FixtureContribution{},
+			Total:         float64(team.Points),
+		}
+	}
+	
+	for _, fixture := range remainingFixtures {
+		homeTeam, awayTeam := ParseEventName(fixture)
+		expectedPoints := homeModel.NewScoreMatrix(fixture, ratings).ExpectedPoints()
+		for i, name := range []string{homeTeam, awayTeam} {
+			breakdown, exists := breakdowns[name]
+			if !exists {
+				continue
+			}
+			breakdown.Fixtures = append(breakdown.Fixtures, FixtureContribution{
+				Fixture:        fixture,
+				ExpectedPoints: expectedPoints[i],
+			})
+			breakdown.Total += expectedPoints[i]
+		}
+	}
+	
+	return breakdowns
+}
+
+// CalcDeterministicGoalDifference projects each team's final goal difference as the
+// current goal difference plus expected goal supremacy over its remaining fixtures
+func CalcDeterministicGoalDifference(leagueTable []Team, remainingFixtures []string, ratings map[string]float64, homeModel HomeAdvantageModel) map[string]float64 {
+	goalDifference := make(map[string]float64)
+	for _, team := range leagueTable {
+		goalDifference[team.Name] = float64(team.GoalDifference)
+	}
+	
+	for _, fixture := range remainingFixtures {
+		homeTeam, awayTeam := ParseEventName(fixture)
+		homeLambda, awayLambda := homeModel.Lambdas(ratings[homeTeam], ratings[awayTeam])
+		goalDifference[homeTeam] += homeLambda - awayLambda
+		goalDifference[awayTeam] += awayLambda - homeLambda
+	}
+	
+	return goalDifference
+}
+
+// DedupeResults drops results repeating an earlier result's (home, away, date), returning
+// the unique results and the duplicates removed, so a fixture listed twice isn't counted
+// twice even under differently formatted names. Results whose teams can't be parsed are
+// kept, to be reported as malformed
+func DedupeResults(results []Result) ([]Result, []Result) {
+	seen := make(map[[3]string]bool, len(results))
+	unique := make([]Result, 0, len(results))
+	var duplicates []Result
+	for _, result := range results {
+		homeTeam, awayTeam := result.Teams()
+		if homeTeam == "" || awayTeam == "" {
+			unique = append(unique, result)
+			continue
+		}
+		key := [3]string{homeTeam, awayTeam, result.Date}
+		if seen[key] {
+			duplicates = append(duplicates, result)
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, result)
+	}
+	return unique, duplicates
+}
+
+// EmpiricalHomeAdvantage returns average home points minus average away points per
+// match across results with a score, as a quick check on the solver's fitted value
+// Returns 0 if no result has a score
+func EmpiricalHomeAdvantage(results []Result) float64 {
+	homePoints, awayPoints, matches := 0, 0, 0
+	for _, result := range results {
+		if !result.Played() {
+			continue
+		}
+		homeGoals, awayGoals := result.Score[0], result.Score[1]
+		if homeGoals > awayGoals {
+			homePoints += 3
+		} else if homeGoals < awayGoals {
+			awayPoints += 3
+		} else {
+			homePoints += 1
+			awayPoints += 1
+		}
+		matches++
+	}
+	if matches == 0 {
+		return 0
+	}
+	return float64(homePoints-awayPoints) / float64(matches)
+}
+
+// PerformanceVsExpected returns, per team, actual points minus the model's expected
+// points over its played results, so a positive value means outperforming the ratings
+// Results involving a team without a rating are skipped
+func PerformanceVsExpected(results []Result, ratings map[string]float64, homeAdvantage float64) map[string]float64 {
+	homeModel := AdditiveHomeAdvantage(homeAdvantage)
+	performance := make(map[string]float64)
+	for _, result := range results {
+		if !result.Played() {
+			continue
+		}
+		homeTeam, awayTeam := result.Teams()
+		if _, exists := ratings[homeTeam]; !exists {
+			continue
+		}
+		if _, exists := ratings[awayTeam]; !exists {
+			continue
+		}
+		
+		expectedPoints := homeModel.NewTeamsScoreMatrix(homeTeam, awayTeam, ratings).ExpectedPoints()
+		homePoints, awayPoints := StandardPointsRule(result.Score[0], result.Score[1])
+		performance[homeTeam] += float64(homePoints) - expectedPoints[0]
+		performance[awayTeam] += float64(awayPoints) - expectedPoints[1]
+	}
+	return performance
+}
+
+// ScheduledFixtures returns the fixture names of results still to be played as of
+// cutoffDate, in date order: unplayed results, plus those dated after a non-empty cutoff
+func ScheduledFixtures(results []Result, cutoffDate string) []string {
+	var scheduled []Result
+	for _, result := range results {
+		if !result.Played() || (cutoffDate != "" && result.Date > cutoffDate) {
+			scheduled = append(scheduled, result)
+		}
+	}
+	sort.SliceStable(scheduled, func(i, j int) bool {
+		return scheduled[i].Date < scheduled[j].Date
+	})
+	
+	fixtures := make([]string, 0, len(scheduled))
+	for _, result := range scheduled {
+		homeTeam, awayTeam := result.Teams()
+		fixtures = append(fixtures, homeTeam+" vs "+awayTeam)
+	}
+	return fixtures
+}
+
+// NextFixtures walks schedule in order and keeps the remaining fixtures that fall within
+// each team's next k games. A fixture is kept only while both teams have fewer than k, so
+// a team can end with fewer than k when its next opponent's window is already full.
+// Teams with no scheduled fixtures fall back to remaining fixtures in generated order,
+// under the same rule, and are returned as fallbackTeams in sorted order
+func NextFixtures(schedule []string, remainingFixtures []string, k int) (window []string, fallbackTeams []string) {
+	remainingCounts := make(map[string]int)
+	for _, fixture := range remainingFixtures {
+		remainingCounts[fixture]++
+	}
+	
+	gamesTaken := make(map[string]int)
+	take := func(fixture string) {
+		homeTeam, awayTeam := ParseEventName(fixture)
+		if remainingCounts[fixture] == 0 || gamesTaken[homeTeam] >= k || gamesTaken[awayTeam] >= k {
+			return
+		}
+		remainingCounts[fixture]--
+		gamesTaken[homeTeam]++
+		gamesTaken[awayTeam]++
+		window = append(window, fixture)
+	}
+	
+	scheduled := make(map[string]bool)
+	for _, fixture := range schedule {
+		homeTeam, awayTeam := ParseEventName(fixture)
+		scheduled[homeTeam] = true
+		scheduled[awayTeam] = true
+		take(fixture)
+	}
+	
+	fallback := make(map[string]bool)
+	for _, fixture := range remainingFixtures {
+		homeTeam, awayTeam := ParseEventName(fixture)
+		for _, team := range []string{homeTeam, awayTeam} {
+			if !scheduled[team] {
+				fallback[team] = true
+			}
+		}
+	}
+	for _, fixture := range remainingFixtures {
+		homeTeam, awayTeam := ParseEventName(fixture)
+		if fallback[homeTeam] || fallback[awayTeam] {
+			take(fixture)
+		}
+	}
+	
+	for team := range fallback {
+		fallbackTeams = append(fallbackTeams, team)
+	}
+	sort.Strings(fallbackTeams)
+	return window, fallbackTeams
+}
+
+// ResultsUpTo returns the results played on or before date (ISO format, compared
+// lexically), so later results become remaining fixtures
+func ResultsUpTo(results []Result, date string) []Result {
+	filtered := make([]Result, 0, len(results))
+	for _, result := range results {
+		if result.Date <= date {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
+
+// HeadToHeadGoals totals the goals teams a and b scored against each other in played results
+func HeadToHeadGoals(results []Result, a, b string) (aGoals, bGoals int) {
+	for _, result := range results {
+		if !result.Played() {
+			continue
+		}
+		homeTeam, awayTeam := result.Teams()
+		if homeTeam == a && awayTeam == b {
+			aGoals += result.Score[0]
+			bGoals += result.Score[1]
+		} else if homeTeam == b && awayTeam == a {
+			aGoals += result.Score[1]
+			bGoals += result.Score[0]
+		}
+	}
+	return aGoals, bGoals
+}
+
+// EventsUpTo returns the events dated on or before date (ISO format, compared lexically)
+func EventsUpTo(events []Event, date string) []Event {
+	filtered := make([]Event, 0, len(events))
+	for _, event := range events {
+		if event.Date <= date {
+			filtered = append(filtered, event)
+		}
+	}
+	return filtered
+}
+
+// ExcludeEvents drops events whose name or date matches any entry in exclusions,
+// e.g. a fixture with known-bad odds
+func ExcludeEvents(events []Event, exclusions []string) []Event {
+	excluded := make(map[string]bool, len(exclusions))
+	for _, exclusion := range exclusions {
+		excluded[exclusion] = true
+	}
+	
+	filtered := make([]Event, 0, len(events))
+	for _, event := range events {
+		if !excluded[event.Name] && !excluded[event.Date] {
+			filtered = append(filtered, event)
+		}
+	}
+	return filtered
+}
+
+// SelectTrainingEvents keeps the most recent window events (events must be sorted by
+// date), then pulls older events, newest first, for any team appearing fewer than
+// minTeamEvents times so every team's rating stays constrained
+// A window of 0 keeps every event
+func SelectTrainingEvents(events []Event, window, minTeamEvents int) []Event {
+	if window <= 0 || window >= len(events) {
+		return events
+	}
+	
+	selected := make([]bool, len(events))
+	counts := make(map[string]int)
+	start := len(events) - window
+	for i := start; i < len(events); i++ {
+		selected[i] = true
+		homeTeam, awayTeam := events[i].Teams()
+		counts[homeTeam]++
+		counts[awayTeam]++
+	}
+	
+	// Top up under-covered teams from older events
+	for i := start - 1; i >= 0 && minTeamEvents > 0; i-- {
+		homeTeam, awayTeam := events[i].Teams()
+		if counts[homeTeam] < minTeamEvents || counts[awayTeam] < minTeamEvents {
+			selected[i] = true
+			counts[homeTeam]++
+			counts[awayTeam]++
+		}
+	}
+	
+	filtered := make([]Event, 0, len(events))
+	for i, event := range events {
+		if selected[i] {
+			filtered = append(filtered, event)
+		}
+	}
+	return filtered
+}
+
+// TableAccuracy returns the mean absolute difference between each team's position in
+// predicted and in actual, both taken as ordered tables. Teams missing from either
+// table are ignored; zero means a perfect prediction
+func TableAccuracy(predicted []Team, actual []Team) float64 {
+	actualPositions := make(map[string]int, len(actual))
+	for i, team := range actual {
+		actualPositions[team.Name] = i
+	}
+	
+	totalError, count := 0.0, 0
+	for i, team := range predicted {
+		if j, exists := actualPositions[team.Name]; exists {
+			totalError += math.Abs(float64(i - j))
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return totalError / float64(count)
+}
@@ -0,0 +1,500 @@
+package outrights
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestDedupeResults(t *testing.T) {
+	tests := []struct {
+		name       string
+		results    []Result
+		wantUnique []string // Names of the results kept, in order
+		wantDupes  int
+	}{
+		{
+			name: "same name and date",
+			results: []Result{
+				{Name: "A vs B", Date: "2024-08-10", Score: []int{1, 0}},
+				{Name: "A vs B", Date: "2024-08-10", Score: []int{1, 0}},
+			},
+			wantUnique: []string{"A vs B"},
+			wantDupes:  1,
+		},
+		{
+			name: "name variant with explicit teams",
+			results: []Result{
+				{Name: "A vs B", Date: "2024-08-10", Score: []int{1, 0}},
+				{Name: "A FC v B", Date: "2024-08-10", HomeTeam: "A", AwayTeam: "B", Score: []int{1, 0}},
+			},
+			wantUnique: []string{"A vs B"},
+			wantDupes:  1,
+		},
+		{
+			name: "reverse fixture and other dates are kept",
+			results: []Result{
+				{Name: "A vs B", Date: "2024-08-10", Score: []int{1, 0}},
+				{Name: "B vs A", Date: "2024-08-10", Score: []int{2, 2}},
+				{Name: "A vs B", Date: "2025-01-04", Score: []int{0, 0}},
+			},
+			wantUnique: []string{"A vs B", "B vs A", "A vs B"},
+		},
+		{
+			name: "unparseable results are kept",
+			results: []Result{
+				{Name: "A - B", Date: "2024-08-10"},
+				{Name: "A - B", Date: "2024-08-10"},
+			},
+			wantUnique: []string{"A - B", "A - B"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unique, duplicates := DedupeResults(tt.results)
+			var names []string
+			for _, result := range unique {
+				names = append(names, result.Name)
+			}
+			if !reflect.DeepEqual(names, tt.wantUnique) {
+				t.Errorf("kept %v, want %v", names, tt.wantUnique)
+			}
+			if len(duplicates) != tt.wantDupes {
+				t.Errorf("dropped %d duplicates, want %d", len(duplicates), tt.wantDupes)
+			}
+		})
+	}
+}
+
+func TestRankAbove(t *testing.T) {
+	tests := []struct {
+		name        string
+		a, b        TableRow
+		tieBreakers []string
+		want        bool
+	}{
+		{"one point beats fifty goals of difference", TableRow{Points: 50, GoalDifference: -25}, TableRow{Points: 49, GoalDifference: 25}, []string{TieBreakGoalDifference}, true},
+		{"goal difference breaks a points tie", TableRow{Points: 50, GoalDifference: 3}, TableRow{Points: 50, GoalDifference: 2}, []string{TieBreakGoalDifference}, true},
+		{"goals for first", TableRow{Points: 50, GoalDifference: 1, GoalsFor: 40}, TableRow{Points: 50, GoalDifference: 5, GoalsFor: 30}, []string{TieBreakGoalsFor, TieBreakGoalDifference}, true},
+		{"later keys only on a tie", TableRow{Points: 50, GoalDifference: 5, Wins: 10}, TableRow{Points: 50, GoalDifference: 5, Wins: 12}, []string{TieBreakGoalDifference, TieBreakWins}, false},
+		{"level on everything", TableRow{Points: 50}, TableRow{Points: 50}, []string{TieBreakGoalDifference}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rankAbove(tt.a, tt.b, tt.tieBreakers); got != tt.want {
+				t.Errorf("rankAbove = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPointsGapBeatsGoalDifference(t *testing.T) {
+	// A wins and draws for 4 points and +1; B wins 53-0 and loses twice for 3 points and +51
+	results := []Result{
+		{Name: "D vs A", Date: "2024-08-17", Score: []int{0, 0}},
+		{Name: "B vs C", Date: "2024-08-10", Score: []int{53, 0}},
+		{Name: "C vs B", Date: "2024-08-17", Score: []int{1, 0}},
+		{Name: "B vs A", Date: "2024-08-24", Score: []int{0, 1}},
+	}
+	table := CalcLeagueTable([]string{"A", "B", "C", "D"}, results, nil)
+	rows := make(map[string]Team)
+	for _, team := range table {
+		rows[team.Name] = team
+	}
+	if rows["A"].Points-rows["B"].Points != 1 || rows["B"].GoalDifference-rows["A"].GoalDifference != 50 {
+		t.Fatalf("expected a 1-point gap against 50 goals of difference, got A %+v, B %+v", teamTableRow(rows["A"]), teamTableRow(rows["B"]))
+	}
+	if table[0].Name != "A" {
+		t.Errorf("expected A top on points, got %s", table[0].Name)
+	}
+
+	// The simulated ranking agrees
+	sp := NewSimPoints(table, 1)
+	positions := sp.PathPositions(nil)
+	if positions["A"][0] >= positions["B"][0] {
+		t.Errorf("simulated positions put A %d and B %d", positions["A"][0], positions["B"][0])
+	}
+}
+
+func TestNextFixtures(t *testing.T) {
+	remaining := []string{"A vs B", "C vs D", "A vs C", "B vs D", "A vs D", "B vs C"}
+	tests := []struct {
+		name         string
+		schedule     []string
+		k            int
+		wantWindow   []string
+		wantFallback []string
+	}{
+		{
+			name:       "one game each in schedule order",
+			schedule:   []string{"A vs C", "B vs D", "A vs B", "C vs D"},
+			k:          1,
+			wantWindow: []string{"A vs C", "B vs D"},
+		},
+		{
+			name:       "two games each",
+			schedule:   []string{"A vs C", "B vs D", "A vs B", "C vs D", "A vs D"},
+			k:          2,
+			wantWindow: []string{"A vs C", "B vs D", "A vs B", "C vs D"},
+		},
+		{
+			name:       "scheduled games already played are skipped",
+			schedule:   []string{"C vs A", "A vs B", "C vs D"},
+			k:          1,
+			wantWindow: []string{"A vs B", "C vs D"},
+		},
+		{
+			name:         "unscheduled team falls back to generated order",
+			schedule:     []string{"A vs B"},
+			k:            1,
+			wantWindow:   []string{"A vs B", "C vs D"},
+			wantFallback: []string{"C", "D"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, fallback := NextFixtures(tt.schedule, remaining, tt.k)
+			if !reflect.DeepEqual(window, tt.wantWindow) {
+				t.Errorf("window = %v, want %v", window, tt.wantWindow)
+			}
+			if !reflect.DeepEqual(fallback, tt.wantFallback) {
+				t.Errorf("fallback = %v, want %v", fallback, tt.wantFallback)
+			}
+		})
+	}
+}
+
+func TestVoidResultsStayUnplayed(t *testing.T) {
+	teamNames := []string{"A", "B"}
+	for _, status := range []string{ResultVoid, ResultAbandoned} {
+		t.Run(status, func(t *testing.T) {
+			results := []Result{{Name: "A vs B", Date: "2024-08-10", Score: []int{3, 0}, Status: status}}
+			for _, team := range CalcLeagueTable(teamNames, results, nil) {
+				if team.Points != 0 || team.Played != 0 || team.GoalDifference != 0 {
+					t.Errorf("%s got %d points from %d games (GD %d), want none", team.Name, team.Points, team.Played, team.GoalDifference)
+				}
+			}
+			remaining := CalcRemainingFixturesWithRounds(teamNames, results, 1, nil)
+			if !reflect.DeepEqual(remaining, []string{"A vs B", "B vs A"}) {
+				t.Errorf("remaining fixtures %v, want both pairings", remaining)
+			}
+		})
+	}
+}
+
+func TestExplicitTeamsOverrideName(t *testing.T) {
+	results := []Result{
+		{Name: "???", HomeTeam: "A", AwayTeam: "B", Date: "2024-08-10", Score: []int{2, 0}},
+		{Name: "B - C (postponed from May)", HomeTeam: "B", AwayTeam: "C", Date: "2024-08-17", Score: []int{1, 1}},
+	}
+	want := map[string]struct{ points, goalDifference, played int }{
+		"A": {3, 2, 1},
+		"B": {1, -2, 2},
+		"C": {1, 0, 1},
+	}
+	for _, team := range CalcLeagueTable([]string{"A", "B", "C"}, results, nil) {
+		got := struct{ points, goalDifference, played int }{team.Points, team.GoalDifference, team.Played}
+		if got != want[team.Name] {
+			t.Errorf("%s = %+v, want %+v", team.Name, got, want[team.Name])
+		}
+	}
+}
+
+func TestEventTeamsOverrideName(t *testing.T) {
+	event := Event{Name: "???", HomeTeam: "A", AwayTeam: "B"}
+	if home, away := event.Teams(); home != "A" || away != "B" {
+		t.Errorf("Teams() = %s, %s, want A, B", home, away)
+	}
+	if home, away := (Event{Name: "A vs B", HomeTeam: "A"}).Teams(); home != "A" || away != "B" {
+		t.Errorf("with one explicit team Teams() = %s, %s, want the parsed A, B", home, away)
+	}
+}
+
+func TestEmpiricalHomeAdvantage(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []Result
+		want    float64
+	}{
+		{"no results", nil, 0},
+		{"only unplayed results", []Result{{Name: "A vs B"}, {Name: "B vs A", Score: []int{1, 0}, Status: ResultVoid}}, 0},
+		{
+			name: "strong home bias",
+			results: []Result{
+				{Name: "A vs B", Score: []int{2, 0}},
+				{Name: "B vs A", Score: []int{1, 0}},
+				{Name: "C vs A", Score: []int{3, 1}},
+				{Name: "B vs C", Score: []int{1, 1}},
+			},
+			want: (10.0 - 1.0) / 4, // Three home wins and a draw
+		},
+		{"balanced", []Result{{Name: "A vs B", Score: []int{1, 0}}, {Name: "B vs A", Score: []int{0, 1}}}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EmpiricalHomeAdvantage(tt.results); got != tt.want {
+				t.Errorf("EmpiricalHomeAdvantage = %g, want %g", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPairingRoundsOverride(t *testing.T) {
+	teamNames := []string{"A", "B", "C"}
+	results := []Result{{Name: "A vs B", Date: "2024-08-10", Score: []int{1, 0}}}
+
+	// An unbalanced schedule: A hosts B twice, and B never hosts C
+	pairingRounds := map[string]int{"A vs B": 2, "B vs C": 0}
+	remaining := CalcRemainingFixturesWithRounds(teamNames, results, 1, pairingRounds)
+	counts := make(map[string]int)
+	for _, fixture := range remaining {
+		counts[fixture]++
+	}
+	want := map[string]int{"A vs B": 1, "A vs C": 1, "B vs A": 1, "C vs A": 1, "C vs B": 1}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("remaining fixture counts %v, want %v", counts, want)
+	}
+}
+
+func TestCalcExpectedPointsBreakdown(t *testing.T) {
+	leagueTable, remainingFixtures, ratings := smallLeague()
+	homeModel := AdditiveHomeAdvantage(0.3)
+	breakdowns := CalcExpectedPointsBreakdown(leagueTable, append(remainingFixtures, "A vs X"), ratings, homeModel)
+	if _, exists := breakdowns["X"]; exists {
+		t.Error("breakdown created for a team outside the league table")
+	}
+
+	for _, team := range leagueTable {
+		breakdown := breakdowns[team.Name]
+		if breakdown.CurrentPoints != team.Points {
+			t.Errorf("%s current points %d, want %d", team.Name, breakdown.CurrentPoints, team.Points)
+		}
+		total := float64(breakdown.CurrentPoints)
+		for _, contribution := range breakdown.Fixtures {
+			total += contribution.ExpectedPoints
+		}
+		if math.Abs(breakdown.Total-total) > 1e-12 {
+			t.Errorf("%s total %.6f, want current points plus fixtures %.6f", team.Name, breakdown.Total, total)
+		}
+	}
+
+	// Each fixture's contributions are the matrix's expected points for either side
+	for _, fixture := range remainingFixtures {
+		homeTeam, awayTeam := ParseEventName(fixture)
+		want := homeModel.NewScoreMatrix(fixture, ratings).ExpectedPoints()
+		for i, name := range []string{homeTeam, awayTeam} {
+			found := false
+			for _, contribution := range breakdowns[name].Fixtures {
+				if contribution.Fixture == fixture {
+					found = true
+					if contribution.ExpectedPoints != want[i] {
+						t.Errorf("%s in %s: %.6f expected points, want %.6f", name, fixture, contribution.ExpectedPoints, want[i])
+					}
+				}
+			}
+			if !found {
+				t.Errorf("%s breakdown is missing %s", name, fixture)
+			}
+		}
+	}
+	if got := len(breakdowns["A"].Fixtures); got != 4 {
+		t.Errorf("A has %d fixture contributions, want 4 including the unknown opponent", got)
+	}
+}
+
+func TestTableAccuracy(t *testing.T) {
+	table := func(names ...string) []Team {
+		teams := make([]Team, len(names))
+		for i, name := range names {
+			teams[i] = Team{Name: name}
+		}
+		return teams
+	}
+	tests := []struct {
+		name      string
+		predicted []Team
+		actual    []Team
+		want      float64
+	}{
+		{"perfect", table("A", "B", "C"), table("A", "B", "C"), 0},
+		{"top two swapped", table("B", "A", "C"), table("A", "B", "C"), 2.0 / 3},
+		{"reversed", table("C", "B", "A"), table("A", "B", "C"), 4.0 / 3},
+		{"unknown team ignored", table("A", "X", "B"), table("A", "B"), 0.5},
+		{"no overlap", table("X"), table("A"), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TableAccuracy(tt.predicted, tt.actual); math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("TableAccuracy = %g, want %g", got, tt.want)
+			}
+		})
+	}
+}
+
+// eventNames lists events' names in order
+func eventNames(events []Event) []string {
+	names := make([]string, len(events))
+	for i, event := range events {
+		names[i] = event.Name
+	}
+	return names
+}
+
+func TestSelectTrainingEvents(t *testing.T) {
+	var events []Event
+	for i, name := range []string{"A vs B", "C vs D", "A vs C", "B vs D", "A vs D", "B vs C"} {
+		events = append(events, Event{Name: name, Date: fmt.Sprintf("2024-08-%02d", 10+i)})
+	}
+	all := eventNames(events)
+	tests := []struct {
+		name          string
+		window        int
+		minTeamEvents int
+		want          []string
+	}{
+		{"zero window keeps everything", 0, 2, all},
+		{"negative window keeps everything", -1, 2, all},
+		{"window covering every event", 10, 2, all},
+		{"window only", 2, 0, []string{"A vs D", "B vs C"}},
+		{"window already covers every team", 2, 1, []string{"A vs D", "B vs C"}},
+		{"top up newest first", 2, 2, []string{"A vs C", "B vs D", "A vs D", "B vs C"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := eventNames(SelectTrainingEvents(events, tt.window, tt.minTeamEvents)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SelectTrainingEvents = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExcludeEvents(t *testing.T) {
+	events := []Event{
+		{Name: "A vs B", Date: "2024-08-10"},
+		{Name: "C vs D", Date: "2024-08-10"},
+		{Name: "A vs C", Date: "2024-08-17"},
+		{Name: "B vs D", Date: "2024-08-24"},
+	}
+	tests := []struct {
+		name       string
+		exclusions []string
+		want       []string
+	}{
+		{"no exclusions", nil, []string{"A vs B", "C vs D", "A vs C", "B vs D"}},
+		{"by name", []string{"A vs C"}, []string{"A vs B", "C vs D", "B vs D"}},
+		{"by date", []string{"2024-08-10"}, []string{"A vs C", "B vs D"}},
+		{"name and date", []string{"B vs D", "2024-08-10"}, []string{"A vs C"}},
+		{"unmatched", []string{"D vs A", "2024-09-01"}, []string{"A vs B", "C vs D", "A vs C", "B vs D"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := eventNames(ExcludeEvents(events, tt.exclusions)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExcludeEvents = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpTo(t *testing.T) {
+	dates := []string{"2024-08-10", "2024-08-17", "2024-08-24"}
+	var results []Result
+	var events []Event
+	for i, date := range dates {
+		name := fmt.Sprintf("T%d vs U%d", i, i)
+		results = append(results, Result{Name: name, Date: date, Score: []int{1, 0}})
+		events = append(events, Event{Name: name, Date: date})
+	}
+	tests := []struct {
+		date string
+		want []string
+	}{
+		{"2024-08-01", []string{}},
+		{"2024-08-17", []string{"T0 vs U0", "T1 vs U1"}}, // Inclusive of the date itself
+		{"2024-08-20", []string{"T0 vs U0", "T1 vs U1"}},
+		{"2024-12-31", []string{"T0 vs U0", "T1 vs U1", "T2 vs U2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			upTo := ResultsUpTo(results, tt.date)
+			resultNames := make([]string, len(upTo))
+			for i, result := range upTo {
+				resultNames[i] = result.Name
+			}
+			if !reflect.DeepEqual(resultNames, tt.want) {
+				t.Errorf("ResultsUpTo = %v, want %v", resultNames, tt.want)
+			}
+			if got := eventNames(EventsUpTo(events, tt.date)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EventsUpTo = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPerformanceVsExpected(t *testing.T) {
+	ratings := map[string]float64{"A": 1.5, "B": 1.0}
+	homeModel := AdditiveHomeAdvantage(0.3)
+	results := []Result{
+		{Name: "A vs B", Score: []int{0, 1}},
+		{Name: "B vs A", Score: []int{1, 1}},
+		{Name: "A vs X", Score: []int{5, 0}}, // X has no rating
+		{Name: "A vs B"},                     // Unplayed
+	}
+	first := homeModel.NewTeamsScoreMatrix("A", "B", ratings).ExpectedPoints()
+	second := homeModel.NewTeamsScoreMatrix("B", "A", ratings).ExpectedPoints()
+	want := map[string]float64{
+		"A": (0 - first[0]) + (1 - second[1]),
+		"B": (3 - first[1]) + (1 - second[0]),
+	}
+
+	performance := PerformanceVsExpected(results, ratings, 0.3)
+	if len(performance) != 2 {
+		t.Errorf("expected performance for A and B only, got %v", performance)
+	}
+	for name, value := range want {
+		if math.Abs(performance[name]-value) > 1e-12 {
+			t.Errorf("%s performance %.6f, want %.6f", name, performance[name], value)
+		}
+	}
+	if performance["A"] >= 0 || performance["B"] <= 0 {
+		t.Errorf("the stronger side took one point from two games, yet performance is %v", performance)
+	}
+}
+
+func TestHeadToHeadGoals(t *testing.T) {
+	results := []Result{
+		{Name: "A vs B", Score: []int{2, 1}},
+		{Name: "B vs A", Score: []int{3, 0}},
+		{Name: "A vs C", Score: []int{4, 4}},
+		{Name: "A vs B"}, // Unplayed
+	}
+	tests := []struct {
+		a, b         string
+		wantA, wantB int
+	}{
+		{"A", "B", 2, 4},
+		{"B", "A", 4, 2},
+		{"A", "C", 4, 4},
+		{"B", "C", 0, 0},
+		{"A", "X", 0, 0},
+	}
+	for _, tt := range tests {
+		if aGoals, bGoals := HeadToHeadGoals(results, tt.a, tt.b); aGoals != tt.wantA || bGoals != tt.wantB {
+			t.Errorf("HeadToHeadGoals(%s, %s) = %d-%d, want %d-%d", tt.a, tt.b, aGoals, bGoals, tt.wantA, tt.wantB)
+		}
+	}
+}
+
+func TestCalcRemainingScheduleStrength(t *testing.T) {
+	ratings := map[string]float64{"A": 1, "B": 2, "C": 1.5, "D": 0.5}
+	fixtures := []string{"A vs B", "C vs A", "B vs C"}
+	want := map[string]float64{
+		"A": 1.75, // B and C
+		"B": 1.25, // A and C
+		"C": 1.5,  // A and B
+		"D": 0,    // Nothing left to play
+	}
+	if got := CalcRemainingScheduleStrength(fixtures, ratings); !reflect.DeepEqual(got, want) {
+		t.Errorf("CalcRemainingScheduleStrength = %v, want %v", got, want)
+	}
+}
@@ -6,15 +6,59 @@ type MatchOdds struct {
 }
 
 type Result struct {
-	Name  string `json:"name"`
-	Date  string `json:"date"`
-	Score []int  `json:"score"`
+	Name     string `json:"name"`
+	Date     string `json:"date"`
+	Score    []int  `json:"score"`
+	HomeTeam string `json:"home_team,omitempty"` // Explicit teams bypass parsing Name when both are set
+	AwayTeam string `json:"away_team,omitempty"`
+	Status   string `json:"status,omitempty"` // ResultVoid or ResultAbandoned excludes the result, leaving the fixture to be replayed
 }
 
 type Event struct {
 	Name      string    `json:"name"`
 	Date      string    `json:"date"`
 	MatchOdds MatchOdds `json:"match_odds"`
+	HomeTeam  string    `json:"home_team,omitempty"` // Explicit teams bypass parsing Name when both are set
+	AwayTeam  string    `json:"away_team,omitempty"`
+	HomeXG    *float64  `json:"home_xg,omitempty"` // Observed expected goals, an optional extra training signal
+	AwayXG    *float64  `json:"away_xg,omitempty"`
+}
+
+// OverroundIssue flags an event whose match odds book looks unsound
+type OverroundIssue struct {
+	Event     string  `json:"event"`
+	Date      string  `json:"date"`
+	Overround float64 `json:"overround"`
+	Reason    string  `json:"reason"`
+	Rejected  bool    `json:"rejected"`
+}
+
+// PointsCheck compares a team's simulated expected season points against the
+// deterministic expected-points model
+type PointsCheck struct {
+	Team          string  `json:"team"`
+	Simulated     float64 `json:"simulated"`
+	Deterministic float64 `json:"deterministic"`
+	Tolerance     float64 `json:"tolerance"`
+	Flagged       bool    `json:"flagged"`
+}
+
+// TableRow is one team's line in a single simulated final table
+type TableRow struct {
+	Team           string `json:"team"`
+	Points         int    `json:"points"`
+	GoalDifference int    `json:"goal_difference"`
+	GoalsFor       int    `json:"goals_for"`
+	Wins           int    `json:"wins"`
+}
+
+// MarketFamily describes a set of "top N" position markets sharing one team selection,
+// expanded into individual markets by ExpandMarketFamily
+type MarketFamily struct {
+	Name      string   `json:"name"`      // Name prefix, e.g. "Top" gives "Top 4", "Top 6"
+	Positions []int    `json:"positions"` // Places paid in each market
+	Include   []string `json:"include,omitempty"`
+	Exclude   []string `json:"exclude,omitempty"`
 }
 
 type Market struct {
@@ -30,11 +74,38 @@ type Team struct {
 	Name                   string    `json:"name"`
 	Points                 int       `json:"points"`
 	GoalDifference         int       `json:"goal_difference"`
+	GoalsFor               int       `json:"goals_for"`
+	Wins                   int       `json:"wins"`
 	Played                 int       `json:"played"`
 	PointsPerGameRating    float64   `json:"points_per_game_rating"`
 	PoissonRating          float64   `json:"poisson_rating"`
+	ExpectedGoalsPerGame   float64   `json:"expected_goals_per_game"`
+	AttackStrength         float64   `json:"attack_strength"`  // Round-robin goals scored per game relative to the league mean
+	DefenceStrength        float64   `json:"defence_strength"` // Round-robin goals conceded per game relative to the league mean (lower is better)
 	ExpectedSeasonPoints   float64   `json:"expected_season_points"`
+	ProjectedRemainingPoints float64 `json:"projected_remaining_points"` // Expected season points less current points
+	ExpectedGoalDifference float64   `json:"expected_goal_difference,omitempty"` // Only set by the expected table
 	PositionProbabilities  []float64 `json:"position_probabilities"`
+	SurvivalProbability    float64   `json:"survival_probability"`
+	QualificationProbabilities map[string]float64 `json:"qualification_probabilities,omitempty"`
+	RemainingScheduleStrength float64 `json:"remaining_schedule_strength"`
+	GoalDifferenceTieBreakRate float64 `json:"goal_difference_tie_break_rate,omitempty"`
+	MostGoalsProbability   float64   `json:"most_goals_probability"` // Chance of scoring the most league goals, dead heats split
+	ExpectedPointsBreakdown *ExpectedPointsBreakdown `json:"expected_points_breakdown,omitempty"`
+}
+
+// ExpectedPointsBreakdown splits a team's deterministic expected season points into
+// points already banked and the expected contribution of each remaining fixture
+type ExpectedPointsBreakdown struct {
+	CurrentPoints int                  `json:"current_points"`
+	Fixtures      []FixtureContribution `json:"fixtures"`
+	Total         float64              `json:"total"`
+}
+
+// FixtureContribution is the expected points a team takes from one remaining fixture
+type FixtureContribution struct {
+	Fixture        string  `json:"fixture"`
+	ExpectedPoints float64 `json:"expected_points"`
 }
 
 type OutrightMark struct {
@@ -48,6 +119,29 @@ type FixtureOdds struct {
 	Probabilities   [3]float64      `json:"probabilities"`    // [home_win, draw, away_win]
 	AsianHandicaps  [][2]interface{} `json:"asian_handicaps"`  // [(handicap, [home_win, away_win] or [home_win, draw, away_win])]
 	TotalGoals      [][2]interface{} `json:"total_goals"`      // [(line, [under, over])]
+	ExactTotalGoals []float64       `json:"exact_total_goals"` // [P(0), P(1), ..., P(>=ExactTotalGoalsMax)]
+	OddEvenGoals    [2]float64      `json:"odd_even_goals"`   // [odd, even]
+	BothTeamsToScore [2]float64     `json:"both_teams_to_score"` // [yes, no]
+	WinningMargins  map[string]float64 `json:"winning_margins"` // {"home_1": p, ..., "draw": p, ..., "away_3+": p}
 	Lambdas         [2]float64      `json:"lambdas"`          // [home_lambda, away_lambda]
+	MostLikelyScore ScoreCell       `json:"most_likely_score"` // Single most probable scoreline
+	Prices          []string        `json:"prices,omitempty"` // [home_win, draw, away_win] fair prices, set when a price format is requested
+	CorrectScores   []ScoreCell     `json:"correct_scores,omitempty"` // Full score grid, only set when pricing a single fixture
 }
 
+// Solver trace stages
+const (
+	SolverTraceInitial    = "initial"
+	SolverTraceGeneration = "generation"
+	SolverTraceFinal      = "final"
+)
+
+// SolverTraceEntry is one step of an opt-in solver trace: the starting ratings, the best
+// candidate after each generation, and the final fit. Initial entries carry ratings only
+type SolverTraceEntry struct {
+	Stage      string             `json:"stage"`
+	Generation int                `json:"generation"` // 1-based for generation entries, otherwise 0
+	Error      float64            `json:"error"`
+	Ratings    map[string]float64 `json:"ratings"`
+	HomeModel  HomeAdvantageModel `json:"home_model"`
+}
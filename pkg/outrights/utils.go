@@ -2,9 +2,24 @@ package outrights
 
 import (
 	"fmt"
+	"log"
+	"math"
 	"strings"
 )
 
+// Margin removal methods for converting prices to probabilities
+const (
+	MarginProportional = "proportional"
+	MarginShin         = "shin"
+	MarginOddsRatio    = "odds_ratio"
+)
+
+// Result statuses excluding a result from the table and remaining fixtures
+const (
+	ResultVoid      = "void"
+	ResultAbandoned = "abandoned"
+)
+
 // Mathematical utility functions
 
 
@@ -40,6 +55,157 @@ func NormalizeProbabilities(prices []float64) ([]float64, error) {
 	return probs, nil
 }
 
+// NormalizeProbabilitiesWithMethod converts betting prices to probabilities using the
+// given margin removal method; an empty method means proportional
+// Shin and odds-ratio both shift more of the margin onto longshots, correcting for
+// the favourite-longshot bias that proportional normalization ignores
+func NormalizeProbabilitiesWithMethod(prices []float64, method string) ([]float64, error) {
+	if err := ValidateMarginMethod(method); err != nil {
+		return nil, err
+	}
+	if method == "" || method == MarginProportional {
+		return NormalizeProbabilities(prices)
+	}
+	
+	overround, err := CalcOverround(prices)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Nothing to remove from a fair (or arbitrage) book
+	if overround <= 1.0 {
+		return NormalizeProbabilities(prices)
+	}
+	
+	implied := make([]float64, len(prices))
+	for i, price := range prices {
+		implied[i] = 1.0 / price
+		if implied[i] >= 1.0 {
+			return nil, fmt.Errorf("price at index %d must be greater than 1.0 for %s method, got %f", i, method, price)
+		}
+	}
+	
+	var probsFn func(x float64) []float64
+	var lo, hi float64
+	
+	if method == MarginShin {
+		// Shin: p_i = (sqrt(z^2 + 4(1-z) q_i^2 / B) - z) / (2(1-z)), solving for insider share z
+		probsFn = func(z float64) []float64 {
+			probs := make([]float64, len(implied))
+			for i, q := range implied {
+				probs[i] = (math.Sqrt(z*z+4*(1-z)*q*q/overround) - z) / (2 * (1 - z))
+			}
+			return probs
+		}
+		lo, hi = 0.0, 0.999
+	} else {
+		// Odds-ratio: implied odds are true odds scaled by c, so p_i = q_i / (c + q_i (1 - c))
+		probsFn = func(c float64) []float64 {
+			probs := make([]float64, len(implied))
+			for i, q := range implied {
+				probs[i] = q / (c + q*(1-c))
+			}
+			return probs
+		}
+		lo, hi = 1.0, 1000.0
+	}
+	
+	// Both families sum to more than 1.0 at lo and decrease monotonically, so bisect
+	for iter := 0; iter < 100; iter++ {
+		mid := (lo + hi) / 2
+		if sumFloats(probsFn(mid)) > 1.0 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	
+	// Absorb any remaining bisection error
+	probs := probsFn((lo + hi) / 2)
+	total := sumFloats(probs)
+	for i := range probs {
+		probs[i] /= total
+	}
+	
+	return probs, nil
+}
+
+// ValidateMarginMethod checks that method names a supported margin removal method
+func ValidateMarginMethod(method string) error {
+	switch method {
+	case "", MarginProportional, MarginShin, MarginOddsRatio:
+		return nil
+	}
+	return fmt.Errorf("unknown margin method %s", method)
+}
+
+// sumFloats returns the sum of a float64 slice
+func sumFloats(values []float64) float64 {
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+// CalcOverround returns the sum of implied probabilities for a set of prices
+// A real book is above 1.0; anything below 1.0 is an arbitrage
+func CalcOverround(prices []float64) (float64, error) {
+	if len(prices) == 0 {
+		return 0, fmt.Errorf("no prices provided")
+	}
+	
+	total := 0.0
+	for i, price := range prices {
+		if price <= 0 {
+			return 0, fmt.Errorf("price at index %d must be positive, got %f", i, price)
+		}
+		total += 1.0 / price
+	}
+	return total, nil
+}
+
+// CheckOverrounds flags events with invalid prices, arbitrage books (overround below 1.0)
+// and, when maxOverround is positive, books outside [minOverround, maxOverround]
+// Flagged events are only dropped from the returned slice when a band is configured
+func CheckOverrounds(events []Event, minOverround, maxOverround float64) ([]Event, []OverroundIssue) {
+	reject := maxOverround > 0
+	accepted := make([]Event, 0, len(events))
+	var issues []OverroundIssue
+	
+	for _, event := range events {
+		overround, err := CalcOverround(event.MatchOdds.Prices)
+		
+		reason := ""
+		if err != nil {
+			reason = err.Error()
+		} else if overround < 1.0 {
+			reason = "overround below 1.0 (arbitrage)"
+		} else if reject && (overround < minOverround || overround > maxOverround) {
+			reason = fmt.Sprintf("overround outside band [%.3f, %.3f]", minOverround, maxOverround)
+		}
+		
+		if reason == "" {
+			accepted = append(accepted, event)
+			continue
+		}
+		
+		log.Printf("Overround check: %s (%s) overround=%.4f: %s", event.Name, event.Date, overround, reason)
+		issues = append(issues, OverroundIssue{
+			Event:     event.Name,
+			Date:      event.Date,
+			Overround: overround,
+			Reason:    reason,
+			Rejected:  reject,
+		})
+		if !reject {
+			accepted = append(accepted, event)
+		}
+	}
+	
+	return accepted, issues
+}
+
 // ParseEventName parses event name into home and away team names
 func ParseEventName(eventName string) (string, string) {
 	parts := strings.Split(eventName, " vs ")
@@ -47,4 +213,28 @@ func ParseEventName(eventName string) (string, string) {
 		return "", ""
 	}
 	return parts[0], parts[1]
-}
\ No newline at end of file
+}
+
+// Teams returns the result's home and away teams, from the explicit fields if both are
+// set and otherwise parsed from Name
+func (r Result) Teams() (string, string) {
+	if r.HomeTeam != "" && r.AwayTeam != "" {
+		return r.HomeTeam, r.AwayTeam
+	}
+	return ParseEventName(r.Name)
+}
+
+// Played reports whether the result has a score and hasn't been voided or abandoned, so
+// it counts towards the table and no longer needs to be played
+func (r Result) Played() bool {
+	return len(r.Score) == 2 && r.Status != ResultVoid && r.Status != ResultAbandoned
+}
+
+// Teams returns the event's home and away teams, from the explicit fields if both are
+// set and otherwise parsed from Name
+func (e Event) Teams() (string, string) {
+	if e.HomeTeam != "" && e.AwayTeam != "" {
+		return e.HomeTeam, e.AwayTeam
+	}
+	return ParseEventName(e.Name)
+}
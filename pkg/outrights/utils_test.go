@@ -0,0 +1,108 @@
+package outrights
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeProbabilitiesWithMethod(t *testing.T) {
+	tests := []struct {
+		name   string
+		prices []float64
+	}{
+		{"three-way book", []float64{1.5, 4.2, 7.0}},
+		{"heavy favourite", []float64{1.12, 8.5, 21}},
+		{"two-way book", []float64{1.4, 2.9}},
+		{"wide margin", []float64{1.25, 3.5, 5.0}},
+	}
+	for _, tt := range tests {
+		for _, method := range []string{MarginShin, MarginOddsRatio} {
+			t.Run(tt.name+"/"+method, func(t *testing.T) {
+				probs, err := NormalizeProbabilitiesWithMethod(tt.prices, method)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if sum := sumFloats(probs); math.Abs(sum-1) > 1e-9 {
+					t.Errorf("probabilities %v sum to %g", probs, sum)
+				}
+
+				// The longshot gives up more margin than under proportional normalization
+				proportional, _ := NormalizeProbabilities(tt.prices)
+				last := len(probs) - 1
+				if probs[last] >= proportional[last] {
+					t.Errorf("longshot %g not shaded below proportional %g", probs[last], proportional[last])
+				}
+				if probs[0] <= proportional[0] {
+					t.Errorf("favourite %g not above proportional %g", probs[0], proportional[0])
+				}
+			})
+		}
+	}
+}
+
+func TestNormalizeProbabilitiesWithMethodEdgeCases(t *testing.T) {
+	// A fair book has no margin to remove, so every method agrees with proportional
+	fair := []float64{2, 4, 4}
+	for _, method := range []string{"", MarginProportional, MarginShin, MarginOddsRatio} {
+		probs, err := NormalizeProbabilitiesWithMethod(fair, method)
+		if err != nil {
+			t.Fatalf("%q: %v", method, err)
+		}
+		for i, want := range []float64{0.5, 0.25, 0.25} {
+			if math.Abs(probs[i]-want) > 1e-12 {
+				t.Errorf("%q: fair book probabilities %v", method, probs)
+				break
+			}
+		}
+	}
+
+	if _, err := NormalizeProbabilitiesWithMethod(fair, "power"); err == nil {
+		t.Error("expected an error for an unknown method")
+	}
+	for _, method := range []string{MarginShin, MarginOddsRatio} {
+		if _, err := NormalizeProbabilitiesWithMethod([]float64{1.0, 5, 10}, method); err == nil {
+			t.Errorf("%s: expected an error for an implied probability of 1", method)
+		}
+	}
+}
+
+func TestCheckOverrounds(t *testing.T) {
+	events := []Event{
+		{Name: "A vs B", MatchOdds: MatchOdds{Prices: []float64{2.0, 3.4, 3.8}}}, // 1.057, a normal book
+		{Name: "C vs D", MatchOdds: MatchOdds{Prices: []float64{2.2, 3.6, 4.0}}}, // 0.982, an arbitrage
+		{Name: "E vs F", MatchOdds: MatchOdds{Prices: []float64{2.0, 0, 3.5}}},   // Invalid price
+		{Name: "G vs H", MatchOdds: MatchOdds{Prices: []float64{1.8, 3.0, 3.2}}}, // 1.201, a wide book
+	}
+	tests := []struct {
+		name         string
+		min, max     float64
+		wantAccepted []string
+		wantIssues   []string
+		wantRejected bool
+	}{
+		{"warn only", 0, 0, []string{"A vs B", "C vs D", "E vs F", "G vs H"}, []string{"C vs D", "E vs F"}, false},
+		{"band rejects", 1.0, 1.15, []string{"A vs B"}, []string{"C vs D", "E vs F", "G vs H"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accepted, issues := CheckOverrounds(events, tt.min, tt.max)
+			var acceptedNames, issueNames []string
+			for _, event := range accepted {
+				acceptedNames = append(acceptedNames, event.Name)
+			}
+			for _, issue := range issues {
+				issueNames = append(issueNames, issue.Event)
+				if issue.Rejected != tt.wantRejected {
+					t.Errorf("%s rejected %t, want %t", issue.Event, issue.Rejected, tt.wantRejected)
+				}
+			}
+			if !reflect.DeepEqual(acceptedNames, tt.wantAccepted) {
+				t.Errorf("accepted %v, want %v", acceptedNames, tt.wantAccepted)
+			}
+			if !reflect.DeepEqual(issueNames, tt.wantIssues) {
+				t.Errorf("issues %v, want %v", issueNames, tt.wantIssues)
+			}
+		})
+	}
+}
@@ -162,60 +162,44 @@ func testParameterSet(paramSet ParameterSet) TestResult {
 		})
 	}
 
-	// Create modified solve-events request
+	// Solve all 16 matches in one batch request with home advantage of 0.3
 	request := endpoints.SolveEventsRequest{
 		Matches:       matches,
 		HomeAdvantage: 0.3,
+		CustomOptions: customOptions(paramSet),
 	}
 
-	// Temporarily modify the default parameters by creating a custom version
-	// We'll have to modify the solve_events.go file or create a custom version
-
 	fmt.Printf("Testing with: Pop=%d, Gen=%d, MutFac=%.3f, Elite=%.2f, InitStd=%.2f, MutProb=%.3f\n", 
 		paramSet.PopulationSize, paramSet.Generations, paramSet.MutationFactor, 
 		paramSet.EliteRatio, paramSet.InitStd, paramSet.MutationProb)
 
-	// Temporarily create a custom version for testing
 	startTime := time.Now()
-
-	// We need to create a custom version that accepts these parameters
-	// For now, let's create a test version that allows parameter passing
-	result := runCustomSolveEvents(request, paramSet)
-
+	summary := endpoints.StabilityReport(request, 1)
 	executionTime := time.Since(startTime)
 
-	// Collect statistics
-	var homeLambdas, awayLambdas, errors []float64
-	for _, solution := range result.Solutions {
-		homeLambdas = append(homeLambdas, solution.Lambdas[0])
-		awayLambdas = append(awayLambdas, solution.Lambdas[1])
-		errors = append(errors, solution.SolverError)
+	if summary.Runs == 0 {
+		log.Fatalf("Solve-events failed for parameter set %s: %v", paramSet.Name, summary.Errors)
 	}
 
-	// Calculate statistics
-	homeMean, homeStd := meanStd(homeLambdas)
-	awayMean, awayStd := meanStd(awayLambdas)
-	errorMean, errorStd := meanStd(errors)
-
 	fmt.Printf("Results: HomeLStd=%.6f, AwayLStd=%.6f, ErrorStd=%.8f, Time=%.0fms\n",
-		homeStd, awayStd, errorStd, float64(executionTime.Nanoseconds())/1e6)
+		summary.HomeLambda.Std, summary.AwayLambda.Std, summary.SolverError.Std, 
+		float64(executionTime.Nanoseconds())/1e6)
 
 	return TestResult{
 		ParameterSet:    paramSet,
-		HomeLambdaMean:  homeMean,
-		HomeLambdaStd:   homeStd,
-		AwayLambdaMean:  awayMean,
-		AwayLambdaStd:   awayStd,
-		ErrorMean:       errorMean,
-		ErrorStd:        errorStd,
+		HomeLambdaMean:  summary.HomeLambda.Mean,
+		HomeLambdaStd:   summary.HomeLambda.Std,
+		AwayLambdaMean:  summary.AwayLambda.Mean,
+		AwayLambdaStd:   summary.AwayLambda.Std,
+		ErrorMean:       summary.SolverError.Mean,
+		ErrorStd:        summary.SolverError.Std,
 		ExecutionTime:   executionTime,
 	}
 }
 
-// Custom solve function that accepts parameter overrides
-func runCustomSolveEvents(request endpoints.SolveEventsRequest, params ParameterSet) endpoints.SolveEventsResult {
-	// Set custom options based on the parameter set
-	customOptions := map[string]interface{}{
+// customOptions converts a parameter set into solve-events option overrides
+func customOptions(params ParameterSet) map[string]interface{} {
+	return map[string]interface{}{
 		"generations":          params.Generations,
 		"population_size":      params.PopulationSize,
 		"mutation_factor":      params.MutationFactor,
@@ -227,83 +211,4 @@ func runCustomSolveEvents(request endpoints.SolveEventsRequest, params Parameter
 		"debug":               false,
 		"use_league_table_init": false,
 	}
-	
-	// Add custom options to the request
-	request.CustomOptions = customOptions
-	
-	result, err := endpoints.SolveEvents(request)
-	if err != nil {
-		log.Fatalf("Solve-events error: %v", err)
-	}
-	
-	return result
-}
-
-// Helper functions for statistics (same as before)
-func minMax(values []float64) (float64, float64) {
-	if len(values) == 0 {
-		return 0, 0
-	}
-	min, max := values[0], values[0]
-	for _, v := range values[1:] {
-		if v < min {
-			min = v
-		}
-		if v > max {
-			max = v
-		}
-	}
-	return min, max
-}
-
-func meanStd(values []float64) (float64, float64) {
-	if len(values) == 0 {
-		return 0, 0
-	}
-	
-	// Calculate mean
-	sum := 0.0
-	for _, v := range values {
-		sum += v
-	}
-	mean := sum / float64(len(values))
-	
-	// Calculate standard deviation
-	sumSquaredDiff := 0.0
-	for _, v := range values {
-		diff := v - mean
-		sumSquaredDiff += diff * diff
-	}
-	std := 0.0
-	if len(values) > 1 {
-		std = sumSquaredDiff / float64(len(values)-1)
-		// Take square root for standard deviation
-		std = sqrt(std)
-	}
-	
-	return mean, std
 }
-
-// Simple square root implementation
-func sqrt(x float64) float64 {
-	if x == 0 {
-		return 0
-	}
-	// Newton's method
-	guess := x / 2
-	for i := 0; i < 20; i++ { // 20 iterations should be enough
-		nextGuess := (guess + x/guess) / 2
-		if abs(guess-nextGuess) < 1e-10 {
-			break
-		}
-		guess = nextGuess
-	}
-	return guess
-}
-
-func abs(x float64) float64 {
-	if x < 0 {
-		return -x
-	}
-	return x
-}
\ No newline at end of file